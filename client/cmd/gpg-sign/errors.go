@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/kjanat/gpg-signing-service/client/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes form a stable contract for scripts and CI systems that need
+// to branch on failure class rather than parse free-form English.
+const (
+	exitOK              = 0
+	exitUsage           = 2
+	exitAuth            = 3
+	exitRateLimited     = 4
+	exitKeyNotFound     = 5
+	exitServiceDegraded = 6
+	exitTimeout         = 7
+	exitNetwork         = 10
+)
+
+// cliError is the machine-readable shape of a CLI failure. In --json
+// mode it is emitted verbatim as the "error" field of the output
+// envelope; otherwise only Message is printed.
+type cliError struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	RetryAfter string `json:"retryAfter,omitempty"`
+	RequestID  string `json:"requestId,omitempty"`
+	exitCode   int
+}
+
+func (e *cliError) Error() string {
+	return e.Message
+}
+
+// classifyError maps err onto the CLI's exit-code/error-code contract
+// by checking the client package's error predicates, then context
+// cancellation, then a generic network error, before falling back to a
+// plain usage error for anything else (bad flags, validation failures).
+func classifyError(err error) *cliError {
+	if err == nil {
+		return nil
+	}
+
+	var alreadyClassified *cliError
+	if errors.As(err, &alreadyClassified) {
+		return alreadyClassified
+	}
+
+	var rateLimitErr *client.RateLimitError
+	var serviceErr *client.ServiceError
+	var netErr net.Error
+
+	switch {
+	case client.IsAuthError(err):
+		return &cliError{Code: "AUTH", Message: err.Error(), exitCode: exitAuth}
+
+	case errors.As(err, &rateLimitErr):
+		ce := &cliError{Code: "RATE_LIMITED", Message: err.Error(), exitCode: exitRateLimited}
+		if rateLimitErr.RetryAfter > 0 {
+			ce.RetryAfter = rateLimitErr.RetryAfter.String()
+		}
+		return ce
+
+	case client.IsKeyNotFound(err):
+		return &cliError{Code: "KEY_NOT_FOUND", Message: err.Error(), exitCode: exitKeyNotFound}
+
+	case client.IsServiceError(err):
+		ce := &cliError{Code: "SERVICE_DEGRADED", Message: err.Error(), exitCode: exitServiceDegraded}
+		if errors.As(err, &serviceErr) {
+			ce.RequestID = serviceErr.RequestID
+		}
+		return ce
+
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return &cliError{Code: "TIMEOUT", Message: err.Error(), exitCode: exitTimeout}
+
+	case errors.As(err, &netErr):
+		return &cliError{Code: "NETWORK", Message: err.Error(), exitCode: exitNetwork}
+
+	default:
+		return &cliError{Code: "USAGE", Message: err.Error(), exitCode: exitUsage}
+	}
+}
+
+// wrapRunE adapts a cobra RunE function so every command funnels its
+// error through classifyError before it reaches main, giving the whole
+// CLI a single point that assigns exit codes and error codes.
+func wrapRunE(fn func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := fn(cmd, args); err != nil {
+			return classifyError(err)
+		}
+		return nil
+	}
+}
+
+// printError reports a command failure on stderr, either as the uniform
+// {"error": {...}} JSON envelope when --json was requested or as plain
+// text otherwise.
+func printError(err error) {
+	ce := classifyError(err)
+
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stderr)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(map[string]*cliError{"error": ce}); encErr != nil {
+			fmt.Fprintln(os.Stderr, "Error:", ce.Message)
+		}
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, "Error:", ce.Message)
+}
+
+// exitCodeFor returns the stable exit code for err, per the CLI's
+// documented exit-code contract.
+func exitCodeFor(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	return classifyError(err).exitCode
+}
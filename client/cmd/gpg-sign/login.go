@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	githubDeviceCodeURL  = "https://github.com/login/device/code"
+	githubAccessTokenURL = "https://github.com/login/oauth/access_token"
+	githubLoginScope     = "read:user"
+)
+
+// persistedToken is the on-disk shape written by `gpg-sign login` to
+// tokenFilePath, and read back by getToken/getAdminToken as a fallback
+// after flags and environment variables.
+type persistedToken struct {
+	Token      string    `json:"token"`
+	AdminToken string    `json:"adminToken,omitempty"`
+	ObtainedAt time.Time `json:"obtainedAt"`
+}
+
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Obtain a signing token via GitHub device-code login",
+	Long: `Drives a GitHub OAuth device flow (the same pattern as Dex's github
+connector): the CLI requests a device and user code from GitHub, you
+approve it in a browser, and the CLI then exchanges the resulting GitHub
+access token at this service's /auth/github endpoint for a scoped
+signing token (and, with --admin, an admin token too). The result is
+saved to $XDG_CONFIG_HOME/gpg-sign/token.json (mode 0600) and used
+automatically by other commands as a fallback after --token/--admin-token
+and their environment variables, so you never have to copy a long-lived
+token onto disk yourself.`,
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
+		clientID, _ := cmd.Flags().GetString("client-id")
+		if clientID == "" {
+			clientID = os.Getenv("GPG_SIGN_GITHUB_CLIENT_ID")
+		}
+		if clientID == "" {
+			return fmt.Errorf("--client-id or GPG_SIGN_GITHUB_CLIENT_ID is required")
+		}
+		admin, _ := cmd.Flags().GetBool("admin")
+
+		ctx := context.Background()
+
+		dc, err := requestGitHubDeviceCode(ctx, clientID, githubLoginScope)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Go to %s and enter code: %s\n", dc.VerificationURI, dc.UserCode)
+
+		ghToken, err := pollGitHubAccessToken(ctx, clientID, dc)
+		if err != nil {
+			return err
+		}
+
+		exchanged, err := exchangeGitHubToken(ctx, ghToken, admin)
+		if err != nil {
+			return err
+		}
+
+		if err := savePersistedToken(&persistedToken{
+			Token:      exchanged.Token,
+			AdminToken: exchanged.AdminToken,
+			ObtainedAt: time.Now(),
+		}); err != nil {
+			return err
+		}
+
+		path, _ := tokenFilePath()
+		fmt.Printf("Logged in. Token saved to %s\n", path)
+		return nil
+	}),
+}
+
+func init() {
+	loginCmd.Flags().String("client-id", "", "GitHub OAuth App client ID (or GPG_SIGN_GITHUB_CLIENT_ID env)")
+	loginCmd.Flags().Bool("admin", false, "Also request an admin token")
+	rootCmd.AddCommand(loginCmd)
+}
+
+// githubDeviceCode is GitHub's response to a device-code request.
+type githubDeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+func requestGitHubDeviceCode(ctx context.Context, clientID, scope string) (*githubDeviceCode, error) {
+	form := url.Values{"client_id": {clientID}, "scope": {scope}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, githubDeviceCodeURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request device code: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request device code: unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	var dc githubDeviceCode
+	if err := json.NewDecoder(resp.Body).Decode(&dc); err != nil {
+		return nil, fmt.Errorf("decode device code response: %w", err)
+	}
+	if dc.Interval <= 0 {
+		dc.Interval = 5
+	}
+	return &dc, nil
+}
+
+// githubAccessTokenPoll is GitHub's response to a device-flow token
+// poll: either an access_token, or an error code like
+// "authorization_pending" while the user hasn't approved yet.
+type githubAccessTokenPoll struct {
+	AccessToken string `json:"access_token"`
+	Error       string `json:"error"`
+}
+
+func pollGitHubAccessToken(ctx context.Context, clientID string, dc *githubDeviceCode) (string, error) {
+	interval := time.Duration(dc.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(dc.ExpiresIn) * time.Second)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("device code expired before login was approved")
+		}
+
+		token, pollErr, err := pollGitHubAccessTokenOnce(ctx, clientID, dc.DeviceCode)
+		if err != nil {
+			return "", err
+		}
+		if token != "" {
+			return token, nil
+		}
+
+		switch pollErr {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		case "expired_token":
+			return "", fmt.Errorf("device code expired before login was approved")
+		case "access_denied":
+			return "", fmt.Errorf("login was denied")
+		default:
+			return "", fmt.Errorf("github device flow error: %s", pollErr)
+		}
+	}
+}
+
+func pollGitHubAccessTokenOnce(ctx context.Context, clientID, deviceCode string) (token, pollErr string, err error) {
+	form := url.Values{
+		"client_id":   {clientID},
+		"device_code": {deviceCode},
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, githubAccessTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("poll for access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tr githubAccessTokenPoll
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return "", "", fmt.Errorf("decode access token response: %w", err)
+	}
+	return tr.AccessToken, tr.Error, nil
+}
+
+// githubExchangeResponse is what POST /auth/github returns once the
+// signing service has validated a GitHub access token.
+type githubExchangeResponse struct {
+	Token      string `json:"token"`
+	AdminToken string `json:"adminToken,omitempty"`
+}
+
+func exchangeGitHubToken(ctx context.Context, githubToken string, admin bool) (*githubExchangeResponse, error) {
+	payload, err := json.Marshal(struct {
+		GitHubToken string `json:"githubToken"`
+		Admin       bool   `json:"admin,omitempty"`
+	}{GitHubToken: githubToken, Admin: admin})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, getBaseURL()+"/auth/github", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("exchange github token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("exchange github token: unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	var er githubExchangeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&er); err != nil {
+		return nil, fmt.Errorf("decode github token exchange response: %w", err)
+	}
+	return &er, nil
+}
+
+// tokenFilePath returns $XDG_CONFIG_HOME/gpg-sign/token.json, falling
+// back to ~/.config/gpg-sign/token.json per the XDG Base Directory spec
+// when XDG_CONFIG_HOME isn't set.
+func tokenFilePath() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("locate config directory: %w", err)
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "gpg-sign", "token.json"), nil
+}
+
+func savePersistedToken(pt *persistedToken) error {
+	path, err := tokenFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(pt, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// loadPersistedToken reads the token saved by a prior `gpg-sign login`,
+// returning nil if none exists or it can't be read.
+func loadPersistedToken() *persistedToken {
+	path, err := tokenFilePath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var pt persistedToken
+	if err := json.Unmarshal(data, &pt); err != nil {
+		return nil
+	}
+	return &pt
+}
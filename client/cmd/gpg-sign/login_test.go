@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTokenFilePathUsesXDGConfigHome(t *testing.T) {
+	previous := os.Getenv("XDG_CONFIG_HOME")
+	defer func() {
+		if err := os.Setenv("XDG_CONFIG_HOME", previous); err != nil {
+			t.Logf("failed to restore XDG_CONFIG_HOME: %v", err)
+		}
+	}()
+
+	if err := os.Setenv("XDG_CONFIG_HOME", "/xdg-config"); err != nil {
+		t.Fatalf("setenv: %v", err)
+	}
+
+	path, err := tokenFilePath()
+	if err != nil {
+		t.Fatalf("tokenFilePath: %v", err)
+	}
+	want := filepath.Join("/xdg-config", "gpg-sign", "token.json")
+	if path != want {
+		t.Errorf("tokenFilePath() = %q, want %q", path, want)
+	}
+}
+
+func TestTokenFilePathFallsBackToHomeConfig(t *testing.T) {
+	previous := os.Getenv("XDG_CONFIG_HOME")
+	defer func() {
+		if err := os.Setenv("XDG_CONFIG_HOME", previous); err != nil {
+			t.Logf("failed to restore XDG_CONFIG_HOME: %v", err)
+		}
+	}()
+
+	if err := os.Unsetenv("XDG_CONFIG_HOME"); err != nil {
+		t.Fatalf("unsetenv: %v", err)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory available: %v", err)
+	}
+
+	path, err := tokenFilePath()
+	if err != nil {
+		t.Fatalf("tokenFilePath: %v", err)
+	}
+	want := filepath.Join(home, ".config", "gpg-sign", "token.json")
+	if path != want {
+		t.Errorf("tokenFilePath() = %q, want %q", path, want)
+	}
+}
+
+func TestSaveAndLoadPersistedTokenRoundTrips(t *testing.T) {
+	previous := os.Getenv("XDG_CONFIG_HOME")
+	defer func() {
+		if err := os.Setenv("XDG_CONFIG_HOME", previous); err != nil {
+			t.Logf("failed to restore XDG_CONFIG_HOME: %v", err)
+		}
+	}()
+	if err := os.Setenv("XDG_CONFIG_HOME", t.TempDir()); err != nil {
+		t.Fatalf("setenv: %v", err)
+	}
+
+	want := &persistedToken{Token: "signing-token", AdminToken: "admin-token", ObtainedAt: time.Now().Truncate(time.Second)}
+	if err := savePersistedToken(want); err != nil {
+		t.Fatalf("savePersistedToken: %v", err)
+	}
+
+	path, err := tokenFilePath()
+	if err != nil {
+		t.Fatalf("tokenFilePath: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat token file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("token file mode = %o, want 0600", perm)
+	}
+
+	got := loadPersistedToken()
+	if got == nil {
+		t.Fatal("loadPersistedToken: got nil")
+	}
+	if got.Token != want.Token || got.AdminToken != want.AdminToken {
+		t.Errorf("loadPersistedToken = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadPersistedTokenReturnsNilWhenMissing(t *testing.T) {
+	previous := os.Getenv("XDG_CONFIG_HOME")
+	defer func() {
+		if err := os.Setenv("XDG_CONFIG_HOME", previous); err != nil {
+			t.Logf("failed to restore XDG_CONFIG_HOME: %v", err)
+		}
+	}()
+	if err := os.Setenv("XDG_CONFIG_HOME", t.TempDir()); err != nil {
+		t.Fatalf("setenv: %v", err)
+	}
+
+	if got := loadPersistedToken(); got != nil {
+		t.Errorf("loadPersistedToken() = %+v, want nil", got)
+	}
+}
+
+func TestGetTokenFallsBackToPersistedFile(t *testing.T) {
+	previousXDG := os.Getenv("XDG_CONFIG_HOME")
+	previousToken := token
+	previousEnv := os.Getenv("GPG_SIGN_TOKEN")
+	defer func() {
+		if err := os.Setenv("XDG_CONFIG_HOME", previousXDG); err != nil {
+			t.Logf("failed to restore XDG_CONFIG_HOME: %v", err)
+		}
+		token = previousToken
+		if err := os.Setenv("GPG_SIGN_TOKEN", previousEnv); err != nil {
+			t.Logf("failed to restore GPG_SIGN_TOKEN: %v", err)
+		}
+	}()
+
+	if err := os.Setenv("XDG_CONFIG_HOME", t.TempDir()); err != nil {
+		t.Fatalf("setenv: %v", err)
+	}
+	token = ""
+	if err := os.Unsetenv("GPG_SIGN_TOKEN"); err != nil {
+		t.Fatalf("unsetenv: %v", err)
+	}
+
+	if err := savePersistedToken(&persistedToken{Token: "persisted-token", AdminToken: "persisted-admin"}); err != nil {
+		t.Fatalf("savePersistedToken: %v", err)
+	}
+
+	if got := getToken(); got != "persisted-token" {
+		t.Errorf("getToken() = %q, want %q", got, "persisted-token")
+	}
+	if got := getAdminToken(); got != "persisted-admin" {
+		t.Errorf("getAdminToken() = %q, want %q", got, "persisted-admin")
+	}
+}
+
+func TestExchangeGitHubTokenSendsTokenAndParsesResponse(t *testing.T) {
+	var gotBody struct {
+		GitHubToken string `json:"githubToken"`
+		Admin       bool   `json:"admin"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/auth/github" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(githubExchangeResponse{Token: "scoped-token", AdminToken: "scoped-admin"})
+	}))
+	defer server.Close()
+
+	previousURL := apiURL
+	defer func() { apiURL = previousURL }()
+	apiURL = server.URL
+
+	got, err := exchangeGitHubToken(context.Background(), "gh-access-token", true)
+	if err != nil {
+		t.Fatalf("exchangeGitHubToken: %v", err)
+	}
+	if got.Token != "scoped-token" || got.AdminToken != "scoped-admin" {
+		t.Errorf("exchangeGitHubToken = %+v", got)
+	}
+	if gotBody.GitHubToken != "gh-access-token" || !gotBody.Admin {
+		t.Errorf("request body = %+v", gotBody)
+	}
+}
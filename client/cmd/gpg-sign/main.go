@@ -2,12 +2,19 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kjanat/gpg-signing-service/client/pkg/client"
@@ -21,11 +28,30 @@ var (
 	adminToken string
 	timeout    time.Duration
 	jsonOutput bool
+	maxRetries int
 )
 
+// signingClient is the subset of *client.Client used by commands that
+// should transparently fail over across multiple endpoints; it's also
+// implemented by *client.Cluster, which newClient/newAdminClient return
+// when --url/GPG_SIGN_URL names more than one endpoint.
+type signingClient interface {
+	Health(ctx context.Context) (*client.HealthStatus, error)
+	WaitForHealthy(ctx context.Context, opts ...client.WaitOption) (*client.HealthWaitResult, error)
+	PublicKey(ctx context.Context, keyID string) (string, error)
+	Sign(ctx context.Context, commitData string, keyID string) (*client.SignResult, error)
+	UploadKey(ctx context.Context, keyID string, armoredPrivateKey string) (*client.KeyInfo, error)
+	ListKeys(ctx context.Context) ([]client.KeyMetadata, error)
+	DeleteKey(ctx context.Context, keyID string) error
+	AdminPublicKey(ctx context.Context, keyID string) (string, error)
+	AuditLogs(ctx context.Context, filter client.AuditFilter) (*client.AuditResult, error)
+	RevokeKey(ctx context.Context, keyID string, req client.RevokeKeyRequest) (*client.RevokeKeyResult, error)
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		printError(err)
+		os.Exit(exitCodeFor(err))
 	}
 }
 
@@ -38,70 +64,130 @@ This tool allows you to:
   - Check service health
   - Retrieve public keys
   - Sign commit data
+  - Verify detached signatures offline
   - Manage keys (admin)
   - Query audit logs (admin)
 
 Environment variables:
-  GPG_SIGN_URL         - API base URL (default: https://gpg.kajkowalski.nl)
+  GPG_SIGN_URL         - API base URL, or a comma-separated list of URLs
+                         to fail over between (default: https://gpg.kajkowalski.nl)
   GPG_SIGN_TOKEN       - OIDC token for signing operations
-  GPG_SIGN_ADMIN_TOKEN - Admin token for administrative operations`,
-	SilenceUsage: true,
+  GPG_SIGN_ADMIN_TOKEN - Admin token for administrative operations
+
+Exit codes: 0 ok, 2 usage, 3 auth, 4 rate-limited, 5 key-not-found,
+6 service-degraded, 7 timeout, 10 network. With --json, a failing
+command emits {"error":{"code":...,"message":...}} on stderr instead of
+the plain-text message.`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVar(&apiURL, "url", "", "API base URL (default: https://gpg.kajkowalski.nl)")
+	rootCmd.PersistentFlags().StringVar(&apiURL, "url", "", "API base URL, or comma-separated URLs to fail over between (default: https://gpg.kajkowalski.nl)")
 	rootCmd.PersistentFlags().StringVar(&token, "token", "", "OIDC token for signing (or GPG_SIGN_TOKEN env)")
 	rootCmd.PersistentFlags().StringVar(&adminToken, "admin-token", "", "Admin token (or GPG_SIGN_ADMIN_TOKEN env)")
 	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 30*time.Second, "Request timeout")
 	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	rootCmd.PersistentFlags().IntVar(&maxRetries, "max-retries", 0, "Max endpoints to try when --url/GPG_SIGN_URL names more than one (default: try every endpoint once)")
 
 	rootCmd.AddCommand(healthCmd)
 	rootCmd.AddCommand(publicKeyCmd)
 	rootCmd.AddCommand(signCmd)
+	rootCmd.AddCommand(signBatchCmd)
+	rootCmd.AddCommand(verifyCmd)
 	rootCmd.AddCommand(adminCmd)
+	rootCmd.AddCommand(waitForCmd)
+	rootCmd.AddCommand(gitCmd)
 }
 
-// getBaseURL returns the API base URL from flags or environment
+// getBaseURL returns the API base URL from flags or environment. When
+// multiple comma-separated URLs are configured, it returns the first one;
+// use getBaseURLs for failover-aware callers.
 func getBaseURL() string {
-	if apiURL != "" {
-		return apiURL
+	return getBaseURLs()[0]
+}
+
+// getBaseURLs returns the API base URL(s) from flags or environment,
+// split on commas, with surrounding whitespace trimmed from each.
+func getBaseURLs() []string {
+	raw := apiURL
+	if raw == "" {
+		raw = os.Getenv("GPG_SIGN_URL")
 	}
-	if url := os.Getenv("GPG_SIGN_URL"); url != "" {
-		return url
+	if raw == "" {
+		raw = "https://gpg.kajkowalski.nl"
+	}
+
+	parts := strings.Split(raw, ",")
+	urls := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			urls = append(urls, p)
+		}
 	}
-	return "https://gpg.kajkowalski.nl"
+	if len(urls) == 0 {
+		urls = []string{"https://gpg.kajkowalski.nl"}
+	}
+	return urls
 }
 
-// getToken returns the OIDC token from flags or environment
+// getToken returns the OIDC token from flags, environment, or (as a last
+// resort) the token file saved by `gpg-sign login`.
 func getToken() string {
 	if token != "" {
 		return token
 	}
-	return os.Getenv("GPG_SIGN_TOKEN")
+	if t := os.Getenv("GPG_SIGN_TOKEN"); t != "" {
+		return t
+	}
+	if pt := loadPersistedToken(); pt != nil {
+		return pt.Token
+	}
+	return ""
 }
 
-// getAdminToken returns the admin token from flags or environment
+// getAdminToken returns the admin token from flags, environment, or (as
+// a last resort) the token file saved by `gpg-sign login --admin`.
 func getAdminToken() string {
 	if adminToken != "" {
 		return adminToken
 	}
-	return os.Getenv("GPG_SIGN_ADMIN_TOKEN")
+	if t := os.Getenv("GPG_SIGN_ADMIN_TOKEN"); t != "" {
+		return t
+	}
+	if pt := loadPersistedToken(); pt != nil {
+		return pt.AdminToken
+	}
+	return ""
 }
 
-// newClient creates a new API client
-func newClient() (*client.Client, error) {
-	return client.New(getBaseURL(),
-		client.WithOIDCToken(getToken()),
-		client.WithTimeout(timeout),
-	)
+// newClient creates a new API client. When --url/GPG_SIGN_URL names more
+// than one endpoint, it returns a *client.Cluster that fails over between
+// them instead of a plain *client.Client.
+func newClient() (signingClient, error) {
+	return newSigningClient(client.WithOIDCToken(getToken()))
 }
 
-// newAdminClient creates a client with admin auth
-func newAdminClient() (*client.Client, error) {
-	return client.New(getBaseURL(),
-		client.WithAdminToken(getAdminToken()),
-		client.WithTimeout(timeout),
-	)
+// newAdminClient creates a client with admin auth. When --url/GPG_SIGN_URL
+// names more than one endpoint, it returns a *client.Cluster that fails
+// over between them instead of a plain *client.Client.
+func newAdminClient() (signingClient, error) {
+	return newSigningClient(client.WithAdminToken(getAdminToken()))
+}
+
+func newSigningClient(authOpt client.Option) (signingClient, error) {
+	urls := getBaseURLs()
+	opts := []client.Option{authOpt, client.WithTimeout(timeout)}
+
+	if len(urls) == 1 {
+		return client.New(urls[0], opts...)
+	}
+
+	var clusterOpts []client.ClusterOption
+	if maxRetries > 0 {
+		clusterOpts = append(clusterOpts, client.WithClusterMaxRetries(maxRetries))
+	}
+	return client.NewCluster(urls, clusterOpts, opts...)
 }
 
 // outputJSON prints the value as JSON
@@ -115,13 +201,28 @@ func outputJSON(v any) error {
 var healthCmd = &cobra.Command{
 	Use:   "health",
 	Short: "Check service health",
-	Long:  "Performs a health check on the GPG signing service.",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	Long: `Performs a health check on the GPG signing service.
+
+With --retry-timeout set to a nonzero duration, health is instead polled
+every --sleep interval - printing the attempt number and remaining
+budget between tries - until the service reports healthy or the retry
+timeout elapses. This mirrors the goss validate retry pattern and is
+useful for container startup and deployment gates where the service may
+still be initializing (loading keys, opening the database) when the CLI
+is first invoked.`,
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
+		retryTimeout, _ := healthCmd.Flags().GetDuration("retry-timeout")
+		sleep, _ := healthCmd.Flags().GetDuration("sleep")
+
 		c, err := newClient()
 		if err != nil {
 			return fmt.Errorf("failed to create client: %w", err)
 		}
 
+		if retryTimeout > 0 {
+			return runHealthRetryLoop(c, retryTimeout, sleep)
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
 
@@ -151,7 +252,88 @@ var healthCmd = &cobra.Command{
 		}
 
 		return nil
-	},
+	}),
+}
+
+// runHealthRetryLoop polls Health every sleep interval, printing the
+// attempt number and remaining retry budget between tries, until the
+// service reports healthy or retryTimeout elapses.
+func runHealthRetryLoop(c signingClient, retryTimeout, sleep time.Duration) error {
+	deadline := time.Now().Add(retryTimeout)
+
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		health, err := c.Health(ctx)
+		cancel()
+
+		if err == nil && health.IsHealthy() {
+			if jsonOutput {
+				return outputJSON(health)
+			}
+			fmt.Printf("Status: %s (attempt %d)\n", health.Status, attempt)
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("timed out after %d attempt(s) waiting for service to become healthy", attempt)
+		}
+
+		status := "unreachable"
+		if health != nil {
+			status = health.Status
+		}
+		fmt.Printf("attempt %d: service not healthy (%s), %s remaining\n", attempt, status, remaining.Round(time.Second))
+
+		wait := sleep
+		if wait > remaining {
+			wait = remaining
+		}
+		time.Sleep(wait)
+	}
+}
+
+func init() {
+	healthCmd.Flags().Duration("sleep", 2*time.Second, "Time to sleep between health checks when --retry-timeout is set")
+	healthCmd.Flags().Duration("retry-timeout", 0, "Keep retrying until healthy or this much time elapses (0 disables retrying)")
+}
+
+// Wait-for command
+var waitForCmd = &cobra.Command{
+	Use:   "waitfor",
+	Short: "Block until the service is healthy",
+	Long: `Polls the health endpoint until the service reports healthy or a
+timeout elapses. Intended for use in Docker/Kubernetes init containers
+that need to gate startup on the signing service coming online.`,
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
+		sleep, _ := cmd.Flags().GetDuration("sleep")
+		retryTimeout, _ := cmd.Flags().GetDuration("retry-timeout")
+
+		c, err := newClient()
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+
+		result, err := c.WaitForHealthy(context.Background(),
+			client.WithHealthSleep(sleep),
+			client.WithHealthRetryTimeout(retryTimeout),
+		)
+		if err != nil {
+			return fmt.Errorf("service did not become healthy: %w", err)
+		}
+
+		if jsonOutput {
+			return outputJSON(result)
+		}
+
+		fmt.Printf("Service healthy after %d attempt(s) (%s)\n", result.Attempts, result.Elapsed)
+		return nil
+	}),
+}
+
+func init() {
+	waitForCmd.Flags().Duration("sleep", 2*time.Second, "Time to sleep between health checks")
+	waitForCmd.Flags().Duration("retry-timeout", 60*time.Second, "Total time to wait before giving up")
 }
 
 // Public key command
@@ -159,7 +341,7 @@ var publicKeyCmd = &cobra.Command{
 	Use:   "public-key",
 	Short: "Get public key",
 	Long:  "Retrieves the public signing key from the service.",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
 		keyID, _ := cmd.Flags().GetString("key-id")
 
 		c, err := newClient()
@@ -184,24 +366,44 @@ var publicKeyCmd = &cobra.Command{
 
 		fmt.Print(pubKey)
 		return nil
-	},
+	}),
 }
 
 func init() {
 	publicKeyCmd.Flags().String("key-id", "", "Key identifier (uses default if not specified)")
 }
 
+// defaultSignStreamThreshold is the stdin size above which sign
+// switches from buffering the whole payload to streaming it, when
+// --chunk-size wasn't given explicitly.
+const defaultSignStreamThreshold = 32 * 1024 * 1024 // 32 MiB
+
 // Sign command
 var signCmd = &cobra.Command{
 	Use:   "sign",
 	Short: "Sign commit data",
 	Long: `Signs commit data read from stdin using the specified key.
 
+Large payloads (release tarballs, container image layers) are streamed
+straight to the service with chunked transfer encoding instead of being
+buffered in memory first: pass --chunk-size to force streaming
+regardless of size, or rely on the ` + "`" + `defaultSignStreamThreshold` + "`" + `
+auto-detection for stdin redirected from a regular file.
+
 Example:
   echo "commit data" | gpg-sign sign --key-id=my-key
-  git log -1 --format='%B' | gpg-sign sign`,
-	RunE: func(cmd *cobra.Command, args []string) error {
+  git log -1 --format='%B' | gpg-sign sign
+  gpg-sign sign --chunk-size=1 < release.tar.gz`,
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
 		keyID, _ := cmd.Flags().GetString("key-id")
+		chunkSize, _ := cmd.Flags().GetInt64("chunk-size")
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if chunkSize > 0 || stdinExceeds(defaultSignStreamThreshold) {
+			return runSignStream(ctx, keyID)
+		}
 
 		// Read data from stdin
 		data, err := io.ReadAll(os.Stdin)
@@ -218,9 +420,6 @@ Example:
 			return fmt.Errorf("failed to create client: %w", err)
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
-		defer cancel()
-
 		result, err := c.Sign(ctx, string(data), keyID)
 		if err != nil {
 			if client.IsAuthError(err) {
@@ -246,11 +445,441 @@ Example:
 
 		fmt.Print(result.Signature)
 		return nil
-	},
+	}),
+}
+
+// stdinExceeds reports whether stdin is a regular file (or pipe exposing
+// its size via Stat) larger than threshold bytes. It returns false,
+// rather than erroring, for anything it can't determine the size of
+// (terminals, sockets), since those aren't what --chunk-size auto-detection
+// is for.
+func stdinExceeds(threshold int64) bool {
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Size() > threshold
+}
+
+// runSignStream signs stdin without buffering it in memory, using
+// client.Client.SignStream. Streaming consumes stdin exactly once, so
+// unlike newClient it doesn't fail over across multiple --url endpoints:
+// it always talks to the first configured base URL directly.
+func runSignStream(ctx context.Context, keyID string) error {
+	c, err := client.New(getBaseURL(), client.WithOIDCToken(getToken()), client.WithTimeout(timeout))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	result, err := c.SignStream(ctx, os.Stdin, keyID)
+	if err != nil {
+		if client.IsAuthError(err) {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+		if client.IsRateLimitError(err) {
+			return fmt.Errorf("rate limit exceeded: %w", err)
+		}
+		return fmt.Errorf("signing failed: %w", err)
+	}
+
+	if jsonOutput {
+		out := map[string]any{"signature": result.Signature}
+		if result.ContentSHA256 != "" {
+			out["contentSha256"] = result.ContentSHA256
+		}
+		if result.RateLimitRemaining != nil {
+			out["rateLimitRemaining"] = *result.RateLimitRemaining
+		}
+		return outputJSON(out)
+	}
+
+	fmt.Print(result.Signature)
+	return nil
 }
 
 func init() {
 	signCmd.Flags().String("key-id", "", "Key identifier (uses default if not specified)")
+	signCmd.Flags().Int64("chunk-size", 0, "Stream stdin instead of buffering it; any value > 0 forces streaming (0 uses size-based auto-detection)")
+}
+
+// signBatchManifestEntry is one line of the NDJSON manifest accepted by
+// signBatchCmd. Exactly one of Path or DataB64 must be set.
+type signBatchManifestEntry struct {
+	ID      string `json:"id"`
+	Path    string `json:"path,omitempty"`
+	DataB64 string `json:"data_b64,omitempty"`
+}
+
+// signBatchResult is one line of the NDJSON result stream written by
+// signBatchCmd. Exactly one of Signature or Error is set.
+type signBatchResult struct {
+	ID        string `json:"id"`
+	Signature string `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+var signBatchCmd = &cobra.Command{
+	Use:   "sign-batch",
+	Short: "Sign many payloads listed in a manifest",
+	Long: `Signs every entry in an NDJSON manifest and writes an NDJSON result
+stream to stdout, preserving the manifest's order.
+
+Each manifest line is a JSON object with an "id" and either a "path" (the
+payload is read from that file) or "data_b64" (the payload is inline,
+base64-encoded):
+
+  {"id":"sbom-1","path":"sbom.json"}
+  {"id":"tag-1","data_b64":"aGVsbG8="}
+
+Up to --concurrency signing requests are kept in flight at once. If the
+service reports it is out of rate-limit headroom, further requests pause
+until the reported reset time before resuming.
+
+Example:
+  gpg-sign sign-batch --manifest artifacts.ndjson --concurrency 8 > results.ndjson`,
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
+		manifestPath, _ := cmd.Flags().GetString("manifest")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		keyID, _ := cmd.Flags().GetString("key-id")
+		failFast, _ := cmd.Flags().GetBool("fail-fast")
+
+		if manifestPath == "" {
+			return fmt.Errorf("--manifest is required")
+		}
+		if concurrency < 1 {
+			concurrency = 1
+		}
+
+		entries, err := readSignBatchManifest(manifestPath)
+		if err != nil {
+			return fmt.Errorf("failed to read manifest: %w", err)
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("manifest is empty")
+		}
+
+		c, err := newClient()
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		results, failed := runSignBatch(ctx, c, entries, keyID, concurrency, failFast)
+
+		enc := json.NewEncoder(os.Stdout)
+		for _, r := range results {
+			if r == nil {
+				continue
+			}
+			if err := enc.Encode(r); err != nil {
+				return fmt.Errorf("failed to write result: %w", err)
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "signed: %d, failed: %d, total: %d\n", len(entries)-failed, failed, len(entries))
+
+		if failFast && failed > 0 {
+			return fmt.Errorf("sign-batch: %d of %d item(s) failed", failed, len(entries))
+		}
+		return nil
+	}),
+}
+
+func init() {
+	signBatchCmd.Flags().String("manifest", "", "Path to an NDJSON manifest of {id, path|data_b64} records")
+	signBatchCmd.Flags().Int("concurrency", 4, "Maximum number of in-flight signing requests")
+	signBatchCmd.Flags().String("key-id", "", "Key identifier (uses default if not specified)")
+	signBatchCmd.Flags().Bool("fail-fast", false, "Stop submitting new requests after the first failure")
+}
+
+// readSignBatchManifest parses an NDJSON manifest into its entries. Each
+// entry's payload is not read or decoded here; signBatchOne resolves it
+// via signBatchPayload just before signing, so a bad path or data_b64
+// fails only that entry.
+func readSignBatchManifest(path string) ([]signBatchManifestEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []signBatchManifestEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry signBatchManifestEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("invalid manifest line %q: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// signBatchPayload resolves a manifest entry to the raw bytes to sign.
+func signBatchPayload(entry signBatchManifestEntry) ([]byte, error) {
+	switch {
+	case entry.Path != "" && entry.DataB64 != "":
+		return nil, fmt.Errorf("entry %q specifies both path and data_b64", entry.ID)
+	case entry.Path != "":
+		return os.ReadFile(entry.Path)
+	case entry.DataB64 != "":
+		return base64.StdEncoding.DecodeString(entry.DataB64)
+	default:
+		return nil, fmt.Errorf("entry %q specifies neither path nor data_b64", entry.ID)
+	}
+}
+
+// signBatchRateGate pauses callers that observe the service is out of
+// rate-limit headroom until the server-reported reset time, so a full
+// --concurrency worth of workers doesn't keep hammering a throttled
+// service.
+type signBatchRateGate struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+func (g *signBatchRateGate) wait(ctx context.Context) error {
+	g.mu.Lock()
+	until := g.until
+	g.mu.Unlock()
+
+	if d := time.Until(until); d > 0 {
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (g *signBatchRateGate) observe(result *client.SignResult) {
+	if result == nil || result.RateLimitRemaining == nil || *result.RateLimitRemaining > 0 || result.RateLimitReset == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if result.RateLimitReset.After(g.until) {
+		g.until = *result.RateLimitReset
+	}
+}
+
+// runSignBatch signs entries with at most concurrency requests in flight,
+// returning one result per entry (nil for entries skipped after a
+// fail-fast abort) in manifest order, plus the number of failures.
+func runSignBatch(ctx context.Context, c signingClient, entries []signBatchManifestEntry, keyID string, concurrency int, failFast bool) ([]*signBatchResult, int) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*signBatchResult, len(entries))
+	sem := make(chan struct{}, concurrency)
+	gate := &signBatchRateGate{}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := 0
+	aborted := false
+
+	for i, entry := range entries {
+		mu.Lock()
+		stop := failFast && aborted
+		mu.Unlock()
+		if stop {
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, entry signBatchManifestEntry) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := signBatchOne(ctx, c, gate, entry, keyID)
+
+			mu.Lock()
+			results[i] = result
+			if result.Error != "" {
+				failed++
+				if failFast {
+					aborted = true
+					cancel()
+				}
+			}
+			mu.Unlock()
+		}(i, entry)
+	}
+
+	wg.Wait()
+	return results, failed
+}
+
+func signBatchOne(ctx context.Context, c signingClient, gate *signBatchRateGate, entry signBatchManifestEntry, keyID string) *signBatchResult {
+	data, err := signBatchPayload(entry)
+	if err != nil {
+		return &signBatchResult{ID: entry.ID, Error: err.Error()}
+	}
+
+	if err := gate.wait(ctx); err != nil {
+		return &signBatchResult{ID: entry.ID, Error: err.Error()}
+	}
+
+	result, err := c.Sign(ctx, string(data), keyID)
+	if err != nil {
+		return &signBatchResult{ID: entry.ID, Error: err.Error()}
+	}
+
+	gate.observe(result)
+	return &signBatchResult{ID: entry.ID, Signature: result.Signature}
+}
+
+// Verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify a detached signature",
+	Long: `Verifies a detached ASCII-armored signature against data read from
+stdin, fetching the signer's public key from the service (or from
+--trust-store, once cached) rather than trusting the caller's own keyring.
+
+Keys are cached on disk by fingerprint, by default under
+$XDG_CACHE_HOME/gpg-sign/keys (or --trust-store, if given), so later runs
+can verify without a network round-trip until the cache entry's TTL
+elapses; pass --refresh to force re-fetching the key from the service
+first.
+
+Example:
+  git log -1 --format='%B' | gpg-sign verify --sig-file commit.sig`,
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
+		sigFile, _ := cmd.Flags().GetString("sig-file")
+		keyID, _ := cmd.Flags().GetString("key-id")
+		trustStore, _ := cmd.Flags().GetString("trust-store")
+		refresh, _ := cmd.Flags().GetBool("refresh")
+
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+		if len(data) == 0 {
+			return fmt.Errorf("no data provided on stdin")
+		}
+
+		signature, err := readSignature(sigFile)
+		if err != nil {
+			return err
+		}
+
+		if trustStore == "" {
+			if dir, err := defaultKeyCacheDir(); err == nil {
+				trustStore = dir
+			}
+		}
+
+		clientOpts := []client.Option{
+			client.WithOIDCToken(getToken()),
+			client.WithTimeout(timeout),
+		}
+		if trustStore != "" {
+			clientOpts = append(clientOpts, client.WithKeyCache(trustStore, 0))
+		}
+
+		c, err := client.New(getBaseURL(), clientOpts...)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+
+		if refresh {
+			c.RefreshKeyCache(keyID)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		result, err := c.Verify(ctx, data, signature, keyID)
+		if err != nil {
+			return fmt.Errorf("verification failed: %w", err)
+		}
+
+		if jsonOutput {
+			return outputJSON(result)
+		}
+
+		if !result.Valid {
+			fmt.Println("signature: INVALID")
+			return fmt.Errorf("signature verification failed")
+		}
+
+		fmt.Println("signature: OK")
+		if result.KeyID != "" {
+			fmt.Printf("  Key ID: %s\n", result.KeyID)
+		}
+		if result.Fingerprint != "" {
+			fmt.Printf("  Fingerprint: %s\n", result.Fingerprint)
+		}
+
+		return nil
+	}),
+}
+
+func init() {
+	verifyCmd.Flags().String("sig-file", "", "Path to the detached ASCII-armored signature (reads fd 3 if omitted)")
+	verifyCmd.Flags().String("key-id", "", "Key identifier the signature was produced with (uses default if not specified)")
+	verifyCmd.Flags().String("trust-store", "", "Directory of pinned public keys, indexed by fingerprint, for offline verification (default: $XDG_CACHE_HOME/gpg-sign/keys)")
+	verifyCmd.Flags().Bool("refresh", false, "Refresh the trust store's cached key from the service before verifying")
+}
+
+// readSignature reads the detached signature from sigFile, or from fd 3
+// when sigFile is empty - the common Unix convention for a second input
+// stream on a CLI that already consumes stdin for its primary payload.
+func readSignature(sigFile string) (string, error) {
+	if sigFile != "" {
+		data, err := os.ReadFile(sigFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read signature file: %w", err)
+		}
+		return string(data), nil
+	}
+
+	sigFD := os.NewFile(3, "signature")
+	if sigFD == nil {
+		return "", fmt.Errorf("--sig-file not given and fd 3 is not open")
+	}
+	if _, err := sigFD.Stat(); err != nil {
+		return "", fmt.Errorf("--sig-file not given and fd 3 is not open")
+	}
+	defer sigFD.Close()
+
+	data, err := io.ReadAll(sigFD)
+	if err != nil {
+		return "", fmt.Errorf("failed to read signature from fd 3: %w", err)
+	}
+	if len(data) == 0 {
+		return "", fmt.Errorf("no signature provided (use --sig-file or pass one on fd 3)")
+	}
+	return string(data), nil
+}
+
+// defaultKeyCacheDir returns $XDG_CACHE_HOME/gpg-sign/keys, falling back
+// to ~/.cache/gpg-sign/keys per the XDG Base Directory spec when
+// XDG_CACHE_HOME isn't set, as verifyCmd's default --trust-store.
+func defaultKeyCacheDir() (string, error) {
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("locate cache directory: %w", err)
+		}
+		cacheHome = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(cacheHome, "gpg-sign", "keys"), nil
 }
 
 // Admin command group
@@ -264,6 +893,7 @@ func init() {
 	adminCmd.AddCommand(adminUploadCmd)
 	adminCmd.AddCommand(adminListCmd)
 	adminCmd.AddCommand(adminDeleteCmd)
+	adminCmd.AddCommand(adminRevokeCmd)
 	adminCmd.AddCommand(adminPublicKeyCmd)
 	adminCmd.AddCommand(adminAuditCmd)
 }
@@ -273,7 +903,7 @@ var adminUploadCmd = &cobra.Command{
 	Use:   "upload",
 	Short: "Upload a signing key",
 	Long:  "Uploads an armored private key to the service.",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
 		keyID, _ := cmd.Flags().GetString("key-id")
 		filePath, _ := cmd.Flags().GetString("file")
 
@@ -319,7 +949,7 @@ var adminUploadCmd = &cobra.Command{
 		}
 
 		return nil
-	},
+	}),
 }
 
 func init() {
@@ -332,7 +962,7 @@ var adminListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all keys",
 	Long:  "Lists metadata for all stored signing keys.",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
 		c, err := newAdminClient()
 		if err != nil {
 			return fmt.Errorf("failed to create client: %w", err)
@@ -367,7 +997,7 @@ var adminListCmd = &cobra.Command{
 		}
 
 		return nil
-	},
+	}),
 }
 
 // Admin delete command
@@ -375,7 +1005,7 @@ var adminDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete a key",
 	Long:  "Permanently deletes a signing key from the service.",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
 		keyID, _ := cmd.Flags().GetString("key-id")
 
 		if keyID == "" {
@@ -411,19 +1041,103 @@ var adminDeleteCmd = &cobra.Command{
 
 		fmt.Printf("Key '%s' deleted successfully\n", keyID)
 		return nil
-	},
+	}),
 }
 
 func init() {
 	adminDeleteCmd.Flags().String("key-id", "", "Key identifier to delete (required)")
 }
 
+// Admin revoke command
+var adminRevokeCmd = &cobra.Command{
+	Use:   "revoke",
+	Short: "Revoke a key",
+	Long: "Revokes a signing key using ACME/OpenPGP-style reason codes. Unlike delete, " +
+		"a revoked key is never removed: it stays on record so verifiers can learn why " +
+		"signatures made with it are no longer trusted. Revocation is permanent; a " +
+		"revoked key can never be reactivated.",
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
+		keyID, _ := cmd.Flags().GetString("key-id")
+		reason, _ := cmd.Flags().GetString("reason")
+		at, _ := cmd.Flags().GetString("at")
+		generateCert, _ := cmd.Flags().GetBool("generate-cert")
+		output, _ := cmd.Flags().GetString("output")
+
+		if keyID == "" {
+			return fmt.Errorf("--key-id is required")
+		}
+
+		req := client.RevokeKeyRequest{
+			Reason:                 client.RevocationReason(reason),
+			GenerateRevocationCert: generateCert,
+		}
+		if at != "" {
+			t, err := time.Parse(time.RFC3339, at)
+			if err != nil {
+				return fmt.Errorf("invalid --at format (use RFC3339): %w", err)
+			}
+			req.At = t
+		}
+
+		c, err := newAdminClient()
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		result, err := c.RevokeKey(ctx, keyID, req)
+		if err != nil {
+			if client.IsAuthError(err) {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
+			if client.IsValidationError(err) {
+				return fmt.Errorf("invalid revocation request: %w", err)
+			}
+			return fmt.Errorf("failed to revoke key: %w", err)
+		}
+
+		if result.RevocationCert != "" && output != "" {
+			if err := os.WriteFile(output, []byte(result.RevocationCert), 0o644); err != nil {
+				return fmt.Errorf("failed to write revocation certificate: %w", err)
+			}
+		}
+
+		if jsonOutput {
+			return outputJSON(result)
+		}
+
+		fmt.Printf("Key '%s' revoked successfully\n", keyID)
+		fmt.Printf("  Reason: %s\n", result.Reason)
+		fmt.Printf("  Revoked at: %s\n", result.RevokedAt.Format(time.RFC3339))
+		if result.RevocationCert != "" {
+			if output != "" {
+				fmt.Printf("  Revocation certificate written to %s\n", output)
+			} else {
+				fmt.Printf("  Revocation certificate:\n%s\n", result.RevocationCert)
+			}
+		}
+
+		return nil
+	}),
+}
+
+func init() {
+	adminRevokeCmd.Flags().String("key-id", "", "Key identifier to revoke (required)")
+	adminRevokeCmd.Flags().String("reason", string(client.RevocationUnspecified),
+		"Revocation reason: key_compromise, superseded, no_longer_used, or unspecified")
+	adminRevokeCmd.Flags().String("at", "", "Revocation timestamp (RFC3339 format, defaults to now)")
+	adminRevokeCmd.Flags().Bool("generate-cert", false, "Ask the service to return an armored OpenPGP revocation certificate")
+	adminRevokeCmd.Flags().String("output", "", "Path to write the revocation certificate (requires --generate-cert)")
+}
+
 // Admin public key command
 var adminPublicKeyCmd = &cobra.Command{
 	Use:   "public-key",
 	Short: "Get public key (admin)",
 	Long:  "Retrieves the public key for a specific key ID via admin endpoint.",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
 		keyID, _ := cmd.Flags().GetString("key-id")
 
 		if keyID == "" {
@@ -456,7 +1170,7 @@ var adminPublicKeyCmd = &cobra.Command{
 
 		fmt.Print(pubKey)
 		return nil
-	},
+	}),
 }
 
 func init() {
@@ -467,23 +1181,42 @@ func init() {
 var adminAuditCmd = &cobra.Command{
 	Use:   "audit",
 	Short: "Query audit logs",
-	Long:  "Retrieves audit log entries with optional filtering.",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	Long: `Retrieves audit log entries with optional filtering.
+
+With --all, transparently pages through the audit log using Limit/Offset
+until the server returns fewer than --limit rows, streaming each entry
+to stdout as it arrives rather than buffering the full result set.
+
+--format selects the output encoding:
+  table   human-readable block per entry (default)
+  json    the full result as one JSON document (disables --all paging)
+  ndjson  one JSON object per line, for piping into jq or a log shipper
+  csv     a fixed column set (id,timestamp,action,subject,key_id,success,
+          error_code,metadata_json), for loading into a SIEM`,
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
 		limit, _ := cmd.Flags().GetInt("limit")
 		offset, _ := cmd.Flags().GetInt("offset")
 		action, _ := cmd.Flags().GetString("action")
 		subject, _ := cmd.Flags().GetString("subject")
 		startDate, _ := cmd.Flags().GetString("start-date")
 		endDate, _ := cmd.Flags().GetString("end-date")
+		all, _ := cmd.Flags().GetBool("all")
+		format, _ := cmd.Flags().GetString("format")
+		if jsonOutput {
+			format = "json"
+		}
+
+		switch format {
+		case "table", "json", "ndjson", "csv":
+		default:
+			return fmt.Errorf("unknown --format %q (want table, json, ndjson, or csv)", format)
+		}
 
 		c, err := newAdminClient()
 		if err != nil {
 			return fmt.Errorf("failed to create client: %w", err)
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
-		defer cancel()
-
 		filter := client.AuditFilter{
 			Limit:   limit,
 			Offset:  offset,
@@ -506,55 +1239,123 @@ var adminAuditCmd = &cobra.Command{
 			filter.EndDate = t
 		}
 
-		result, err := c.AuditLogs(ctx, filter)
-		if err != nil {
-			if client.IsAuthError(err) {
-				return fmt.Errorf("authentication failed: %w", err)
-			}
-			return fmt.Errorf("failed to get audit logs: %w", err)
-		}
+		if format == "json" {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
 
-		if jsonOutput {
-			// We need to match the structure of the original output if possible, or just dump the result
-			// The wrapper returns AuditResult which has Logs []AuditLog
-			// The original returned the raw JSON200 which had Logs *[]AuditLog
-			// It should be close enough.
+			result, err := c.AuditLogs(ctx, filter)
+			if err != nil {
+				if client.IsAuthError(err) {
+					return fmt.Errorf("authentication failed: %w", err)
+				}
+				return fmt.Errorf("failed to get audit logs: %w", err)
+			}
 			return outputJSON(result)
 		}
 
-		if len(result.Logs) == 0 {
-			fmt.Println("No audit logs found")
-			return nil
+		csvWriter := csv.NewWriter(os.Stdout)
+		if format == "csv" {
+			if err := csvWriter.Write([]string{"id", "timestamp", "action", "subject", "key_id", "success", "error_code", "metadata_json"}); err != nil {
+				return fmt.Errorf("failed to write csv header: %w", err)
+			}
 		}
 
-		fmt.Printf("Audit logs (%d entries):\n", result.Count)
-		for _, log := range result.Logs {
-			fmt.Printf("\n  ID: %s\n", log.ID)
-			fmt.Printf("    Timestamp: %s\n", log.Timestamp)
-			fmt.Printf("    Action: %s\n", log.Action)
-			fmt.Printf("    Subject: %s\n", log.Subject)
-			fmt.Printf("    Key ID: %s\n", log.KeyID)
-			fmt.Printf("    Success: %v\n", log.Success)
-			if log.ErrorCode != nil {
-				fmt.Printf("    Error: %s\n", *log.ErrorCode)
-			}
-			if len(log.Metadata) > 0 {
-				// Pretty print metadata if it's valid JSON
-				var meta map[string]any
-				if err := json.Unmarshal(log.Metadata, &meta); err == nil {
-					parts := make([]string, 0, len(meta))
-					for k, v := range meta {
-						parts = append(parts, fmt.Sprintf("%s=%v", k, v))
-					}
-					fmt.Printf("    Metadata: %s\n", strings.Join(parts, ", "))
-				} else {
-					fmt.Printf("    Metadata: %s\n", string(log.Metadata))
+		total := 0
+		for page := 0; ; page++ {
+			pageFilter := filter
+			pageFilter.Offset = filter.Offset + page*filter.Limit
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			result, err := c.AuditLogs(ctx, pageFilter)
+			cancel()
+			if err != nil {
+				if client.IsAuthError(err) {
+					return fmt.Errorf("authentication failed: %w", err)
+				}
+				return fmt.Errorf("failed to get audit logs: %w", err)
+			}
+
+			if page == 0 && len(result.Logs) == 0 && format == "table" {
+				fmt.Println("No audit logs found")
+				return nil
+			}
+
+			for _, log := range result.Logs {
+				if err := writeAuditLogEntry(csvWriter, format, log); err != nil {
+					return fmt.Errorf("failed to write audit log entry: %w", err)
 				}
 			}
+			total += len(result.Logs)
+
+			if !all || len(result.Logs) < filter.Limit {
+				break
+			}
 		}
 
+		if format == "table" {
+			fmt.Printf("\n%d entries\n", total)
+		}
 		return nil
-	},
+	}),
+}
+
+// writeAuditLogEntry renders a single audit log entry in the given
+// format, writing it immediately to stdout so adminAuditCmd can stream
+// entries as they arrive rather than buffering the full result set.
+func writeAuditLogEntry(csvWriter *csv.Writer, format string, log client.AuditLog) error {
+	switch format {
+	case "ndjson":
+		enc := json.NewEncoder(os.Stdout)
+		return enc.Encode(log)
+
+	case "csv":
+		errorCode := ""
+		if log.ErrorCode != nil {
+			errorCode = *log.ErrorCode
+		}
+		metadata := ""
+		if len(log.Metadata) > 0 {
+			metadata = string(log.Metadata)
+		}
+		if err := csvWriter.Write([]string{
+			log.ID,
+			log.Timestamp.Format(time.RFC3339),
+			log.Action,
+			log.Subject,
+			log.KeyID,
+			strconv.FormatBool(log.Success),
+			errorCode,
+			metadata,
+		}); err != nil {
+			return err
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+
+	default: // table
+		fmt.Printf("\n  ID: %s\n", log.ID)
+		fmt.Printf("    Timestamp: %s\n", log.Timestamp)
+		fmt.Printf("    Action: %s\n", log.Action)
+		fmt.Printf("    Subject: %s\n", log.Subject)
+		fmt.Printf("    Key ID: %s\n", log.KeyID)
+		fmt.Printf("    Success: %v\n", log.Success)
+		if log.ErrorCode != nil {
+			fmt.Printf("    Error: %s\n", *log.ErrorCode)
+		}
+		if len(log.Metadata) > 0 {
+			var meta map[string]any
+			if err := json.Unmarshal(log.Metadata, &meta); err == nil {
+				parts := make([]string, 0, len(meta))
+				for k, v := range meta {
+					parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+				}
+				fmt.Printf("    Metadata: %s\n", strings.Join(parts, ", "))
+			} else {
+				fmt.Printf("    Metadata: %s\n", string(log.Metadata))
+			}
+		}
+		return nil
+	}
 }
 
 func init() {
@@ -564,4 +1365,285 @@ func init() {
 	adminAuditCmd.Flags().String("subject", "", "Filter by subject")
 	adminAuditCmd.Flags().String("start-date", "", "Start date (RFC3339 format)")
 	adminAuditCmd.Flags().String("end-date", "", "End date (RFC3339 format)")
+	adminAuditCmd.Flags().Bool("all", false, "Page through every entry instead of stopping at --limit")
+	adminAuditCmd.Flags().String("format", "table", "Output format: table, json, ndjson, or csv")
+}
+
+// Git command group
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Wire the CLI into git as a gpg.program signing backend",
+	Long: `Commands for using gpg-sign as git's signing backend instead of a
+local gpg-agent, so commits and tags are signed by the remote service.`,
+}
+
+func init() {
+	gitCmd.AddCommand(gitInstallCmd)
+	gitCmd.AddCommand(gitUninstallCmd)
+	gitCmd.AddCommand(gitProgramCmd)
+}
+
+// Git install command
+var gitInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Configure git to sign commits/tags through this CLI",
+	Long: `Sets gpg.program, commit.gpgsign, and user.signingkey (when
+--key-id is given) so git invokes "gpg-sign git program" instead of a
+local gpg binary whenever it needs to sign a commit or tag.
+
+By default this configures the current repository; pass --global to
+configure it for the current user instead.`,
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
+		global, _ := cmd.Flags().GetBool("global")
+		keyID, _ := cmd.Flags().GetString("key-id")
+
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to locate gpg-sign binary: %w", err)
+		}
+
+		if err := gitConfigSet(global, "gpg.program", fmt.Sprintf("%s git program", exe)); err != nil {
+			return err
+		}
+		if err := gitConfigSet(global, "commit.gpgsign", "true"); err != nil {
+			return err
+		}
+		if keyID != "" {
+			if err := gitConfigSet(global, "user.signingkey", keyID); err != nil {
+				return err
+			}
+		}
+
+		scope := "this repository"
+		if global {
+			scope = "the current user"
+		}
+		fmt.Printf("Configured %s to sign commits through %s\n", scope, exe)
+		return nil
+	}),
+}
+
+func init() {
+	gitInstallCmd.Flags().Bool("global", false, "Configure git globally instead of for the current repository")
+	gitInstallCmd.Flags().String("key-id", "", "Key identifier to set as user.signingkey")
+}
+
+// Git uninstall command
+var gitUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the gpg-sign git signing configuration",
+	Long: `Unsets gpg.program and commit.gpgsign, restoring git to its
+default signing backend. Pass --global to undo a --global install.`,
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
+		global, _ := cmd.Flags().GetBool("global")
+
+		if err := gitConfigUnset(global, "gpg.program"); err != nil {
+			return err
+		}
+		if err := gitConfigUnset(global, "commit.gpgsign"); err != nil {
+			return err
+		}
+
+		scope := "this repository"
+		if global {
+			scope = "the current user"
+		}
+		fmt.Printf("Removed gpg-sign git configuration from %s\n", scope)
+		return nil
+	}),
+}
+
+func init() {
+	gitUninstallCmd.Flags().Bool("global", false, "Remove the global configuration instead of the current repository's")
+}
+
+// gitConfigSet runs "git config [--global] key value".
+func gitConfigSet(global bool, key, value string) error {
+	args := []string{"config"}
+	if global {
+		args = append(args, "--global")
+	}
+	args = append(args, key, value)
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("git config %s failed: %w: %s", key, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// gitConfigUnset runs "git config [--global] --unset key", tolerating
+// the case where the key was never set.
+func gitConfigUnset(global bool, key string) error {
+	args := []string{"config"}
+	if global {
+		args = append(args, "--global")
+	}
+	args = append(args, "--unset", key)
+	cmd := exec.Command("git", args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil && cmd.ProcessState.ExitCode() != 5 { // 5: key not set
+		return fmt.Errorf("git config --unset %s failed: %w: %s", key, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Git program command
+var gitProgramCmd = &cobra.Command{
+	Use:   "program",
+	Short: "Act as the gpg binary git invokes to sign or verify a commit or tag",
+	Long: `Implements the small subset of the gpg CLI that git actually
+invokes when commit.gpgsign/tag.gpgSign is enabled, or when verifying a
+signed commit/tag.
+
+In its default (signing) mode it reads the payload to be signed from
+stdin, signs it through the GPG Signing Service, and writes the armored
+detached signature to stdout, emitting gpg's "[GNUPG:] ..."
+status-protocol lines (BEGIN_SIGNING, KEY_CONSIDERED, SIG_CREATED) to the
+file descriptor named by --status-fd so git can parse the outcome the
+same way it would for a real gpg-agent.
+
+With --verify <sigfile> [datafile], it instead checks the detached
+signature in sigfile against datafile (or stdin, if datafile is
+omitted) using the same public-key cache as "gpg-sign verify", and
+emits GOODSIG/VALIDSIG or BADSIG so "git log --show-signature" and
+"git verify-commit" report the outcome correctly.
+
+This is not installed directly; "gpg-sign git install" points git's
+gpg.program at it.`,
+	RunE: wrapRunE(func(cmd *cobra.Command, args []string) error {
+		verify, _ := cmd.Flags().GetBool("verify")
+		if verify {
+			return runGitProgramVerify(cmd, args)
+		}
+		return runGitProgramSign(cmd)
+	}),
+}
+
+func runGitProgramSign(cmd *cobra.Command) error {
+	statusFD, _ := cmd.Flags().GetInt("status-fd")
+	keyID, _ := cmd.Flags().GetString("local-user")
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("failed to read payload: %w", err)
+	}
+
+	status := statusWriter(statusFD)
+	defer status.close()
+
+	status.printf("BEGIN_SIGNING")
+
+	c, err := newClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	status.printf("KEY_CONSIDERED %s 0", keyID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result, err := c.Sign(ctx, string(data), keyID)
+	if err != nil {
+		return fmt.Errorf("signing failed: %w", err)
+	}
+
+	status.printf("SIG_CREATED D 1 10 00 %d %s", time.Now().Unix(), keyID)
+	fmt.Print(result.Signature)
+	return nil
+}
+
+// runGitProgramVerify implements "gpg --status-fd=N --verify <sigfile>
+// [datafile]": args[0] is the detached signature, args[1] (or stdin, if
+// omitted) is the data it was produced over.
+func runGitProgramVerify(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("--verify requires a signature file argument")
+	}
+
+	statusFD, _ := cmd.Flags().GetInt("status-fd")
+	status := statusWriter(statusFD)
+	defer status.close()
+
+	sigBytes, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read signature file: %w", err)
+	}
+
+	var data []byte
+	if len(args) >= 2 && args[1] != "-" {
+		data, err = os.ReadFile(args[1])
+	} else {
+		data, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read signed data: %w", err)
+	}
+
+	trustStore, err := defaultKeyCacheDir()
+	if err != nil {
+		return err
+	}
+
+	c, err := client.New(getBaseURL(),
+		client.WithOIDCToken(getToken()),
+		client.WithTimeout(timeout),
+		client.WithKeyCache(trustStore, 0),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result, err := c.Verify(ctx, data, string(sigBytes), "")
+	if err != nil {
+		return fmt.Errorf("verification failed: %w", err)
+	}
+
+	if !result.Valid {
+		status.printf("BADSIG 0 unknown")
+		return fmt.Errorf("signature verification failed")
+	}
+
+	status.printf("VALIDSIG %s 0 0 0 0 0 0 0 %s", result.Fingerprint, result.Fingerprint)
+	status.printf("GOODSIG %s %s", result.KeyID, result.KeyID)
+	return nil
+}
+
+func init() {
+	gitProgramCmd.Flags().Int("status-fd", 2, "File descriptor to write the [GNUPG:] status protocol to")
+	gitProgramCmd.Flags().BoolP("detach-sign", "b", false, "Make a detached signature (the only mode supported)")
+	gitProgramCmd.Flags().BoolP("sign", "s", false, "Make a signature (accepted for gpg compatibility)")
+	gitProgramCmd.Flags().BoolP("armor", "a", false, "Create ASCII armored output (always on)")
+	gitProgramCmd.Flags().StringP("local-user", "u", "", "Key identifier to sign with")
+	gitProgramCmd.Flags().Bool("verify", false, "Verify a detached signature instead of signing")
+	gitProgramCmd.Flags().String("keyid-format", "", "Accepted for gpg compatibility (git passes it when verifying); ignored")
+}
+
+// gpgStatus writes gpg's "[GNUPG:] ..." status-protocol lines to the
+// file descriptor git passed via --status-fd, falling back to stderr
+// when that descriptor can't be opened (e.g. under a plain shell).
+type gpgStatus struct {
+	w      io.Writer
+	closer func() error
+}
+
+func statusWriter(fd int) gpgStatus {
+	f := os.NewFile(uintptr(fd), "status-fd")
+	if f == nil {
+		return gpgStatus{w: os.Stderr, closer: func() error { return nil }}
+	}
+	if _, err := f.Stat(); err != nil {
+		return gpgStatus{w: os.Stderr, closer: func() error { return nil }}
+	}
+	return gpgStatus{w: f, closer: f.Close}
+}
+
+func (s gpgStatus) printf(format string, args ...any) {
+	fmt.Fprintf(s.w, "[GNUPG:] "+format+"\n", args...)
+}
+
+func (s gpgStatus) close() {
+	_ = s.closer()
 }
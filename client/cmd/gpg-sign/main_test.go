@@ -1,15 +1,22 @@
 package main
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
 	"github.com/kjanat/gpg-signing-service/client/pkg/client"
 	"github.com/spf13/cobra"
 )
@@ -78,6 +85,37 @@ func TestGetBaseURL(t *testing.T) {
 	}
 }
 
+func TestGetBaseURLsSplitsOnComma(t *testing.T) {
+	previousAPIURL := apiURL
+	defer func() { apiURL = previousAPIURL }()
+
+	apiURL = "http://a.example, http://b.example ,http://c.example"
+	got := getBaseURLs()
+	want := []string{"http://a.example", "http://b.example", "http://c.example"}
+	if len(got) != len(want) {
+		t.Fatalf("getBaseURLs() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("getBaseURLs()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNewClientReturnsClusterForMultipleURLs(t *testing.T) {
+	previousAPIURL := apiURL
+	defer func() { apiURL = previousAPIURL }()
+
+	apiURL = "http://a.example,http://b.example"
+	c, err := newClient()
+	if err != nil {
+		t.Fatalf("newClient: %v", err)
+	}
+	if _, ok := c.(*client.Cluster); !ok {
+		t.Errorf("newClient() = %T, want *client.Cluster", c)
+	}
+}
+
 // TestGetToken tests token resolution
 //
 //nolint:dupl // similar structure to TestGetBaseURL; duplication is fine for clarity
@@ -483,6 +521,85 @@ func TestSignCommand(t *testing.T) {
 	}
 }
 
+// TestSignCommandStreamsWhenChunkSizeSet verifies that --chunk-size
+// routes sign through SignStream against /sign/stream instead of
+// buffering stdin and posting to /sign.
+func TestSignCommandStreamsWhenChunkSizeSet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sign/stream" {
+			t.Errorf("expected /sign/stream, got %s", r.URL.Path)
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body error: %v", err)
+		}
+		if string(body) != "test commit data" {
+			t.Errorf("expected 'test commit data', got %s", string(body))
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		if _, err := w.Write([]byte("-----BEGIN PGP SIGNATURE-----\ntest signature\n-----END PGP SIGNATURE-----")); err != nil {
+			t.Errorf("write error: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	previousURL := apiURL
+	previousToken := token
+	previousStdin := os.Stdin
+	defer func() {
+		apiURL = previousURL
+		token = previousToken
+		os.Stdin = previousStdin
+	}()
+
+	apiURL = server.URL
+	token = "test-token"
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe error: %v", err)
+	}
+	if _, err := w.Write([]byte("test commit data")); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+	os.Stdin = r
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("key-id", "", "")
+	cmd.Flags().Int64("chunk-size", 1, "")
+
+	previousStdout := os.Stdout
+	rOut, wOut, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe error: %v", err)
+	}
+	os.Stdout = wOut
+
+	err = signCmd.RunE(cmd, nil)
+
+	if err := wOut.Close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+	os.Stdout = previousStdout
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	out, err := io.ReadAll(rOut)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if !strings.Contains(string(out), "BEGIN PGP SIGNATURE") {
+		t.Error("expected PGP signature in output")
+	}
+}
+
 // TestSignCommandNoData tests sign command with no input
 func TestSignCommandNoData(t *testing.T) {
 	previousStdin := os.Stdin
@@ -509,6 +626,91 @@ func TestSignCommandNoData(t *testing.T) {
 	}
 }
 
+// TestSignBatchCommand tests batch signing against an NDJSON manifest
+func TestSignBatchCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sign" {
+			t.Errorf("expected /sign, got %s", r.URL.Path)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body error: %v", err)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		if _, err := w.Write([]byte("sig-for-" + string(body))); err != nil {
+			t.Errorf("write error: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	previousURL := apiURL
+	previousToken := token
+	defer func() {
+		apiURL = previousURL
+		token = previousToken
+	}()
+	apiURL = server.URL
+	token = "test-token"
+
+	manifest := `{"id":"a","data_b64":"` + base64.StdEncoding.EncodeToString([]byte("payload-a")) + `"}
+{"id":"b","data_b64":"` + base64.StdEncoding.EncodeToString([]byte("payload-b")) + `"}
+`
+	manifestFile := filepath.Join(t.TempDir(), "manifest.ndjson")
+	if err := os.WriteFile(manifestFile, []byte(manifest), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("manifest", manifestFile, "")
+	cmd.Flags().Int("concurrency", 2, "")
+	cmd.Flags().String("key-id", "", "")
+	cmd.Flags().Bool("fail-fast", false, "")
+
+	previousStdout := os.Stdout
+	rOut, wOut, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe error: %v", err)
+	}
+	os.Stdout = wOut
+
+	err = signBatchCmd.RunE(cmd, nil)
+
+	if closeErr := wOut.Close(); closeErr != nil {
+		t.Fatalf("close error: %v", closeErr)
+	}
+	os.Stdout = previousStdout
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := io.ReadAll(rOut)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 result lines, got %d: %q", len(lines), out)
+	}
+
+	var first signBatchResult
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("unmarshal first result: %v", err)
+	}
+	if first.ID != "a" || first.Signature != "sig-for-payload-a" {
+		t.Errorf("unexpected first result: %+v", first)
+	}
+
+	var second signBatchResult
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("unmarshal second result: %v", err)
+	}
+	if second.ID != "b" || second.Signature != "sig-for-payload-b" {
+		t.Errorf("unexpected second result: %+v", second)
+	}
+}
+
 // TestAdminUploadCommand tests key upload
 func TestAdminUploadCommand(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -690,6 +892,120 @@ func TestAdminDeleteCommand(t *testing.T) {
 	}
 }
 
+// TestAdminRevokeCommand tests key revocation, paralleling
+// TestAdminDeleteCommand, and asserts the returned revocation certificate
+// is written to --output.
+func TestAdminRevokeCommand(t *testing.T) {
+	const cert = "-----BEGIN PGP PUBLIC KEY BLOCK-----\nrevocation\n-----END PGP PUBLIC KEY BLOCK-----"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/admin/keys/") || !strings.HasSuffix(r.URL.Path, "/revoke") {
+			t.Errorf("expected /admin/keys/*/revoke, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(map[string]any{
+			"success":        true,
+			"revocationCert": cert,
+		}); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	previousURL := apiURL
+	previousAdminToken := adminToken
+	defer func() {
+		apiURL = previousURL
+		adminToken = previousAdminToken
+	}()
+
+	apiURL = server.URL
+	adminToken = testAdminToken
+
+	outputPath := filepath.Join(t.TempDir(), "revocation.asc")
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("key-id", "test-key", "")
+	cmd.Flags().String("reason", string(client.RevocationKeyCompromise), "")
+	cmd.Flags().String("at", "", "")
+	cmd.Flags().Bool("generate-cert", true, "")
+	cmd.Flags().String("output", outputPath, "")
+
+	previousStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe error: %v", err)
+	}
+	os.Stdout = w
+
+	err = adminRevokeCmd.RunE(cmd, nil)
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+	os.Stdout = previousStdout
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	output := string(out)
+
+	if !strings.Contains(output, "revoked successfully") {
+		t.Error("expected success message in output")
+	}
+	if !strings.Contains(output, outputPath) {
+		t.Error("expected output path to be mentioned in output")
+	}
+
+	written, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read written revocation cert: %v", err)
+	}
+	if string(written) != cert {
+		t.Errorf("written cert = %q, want %q", string(written), cert)
+	}
+}
+
+// TestAdminRevokeCommandRejectsUnknownReason asserts that an unrecognized
+// --reason is rejected before any network call is made.
+func TestAdminRevokeCommandRejectsUnknownReason(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected no network call for an invalid revocation reason")
+	}))
+	defer server.Close()
+
+	previousURL := apiURL
+	previousAdminToken := adminToken
+	defer func() {
+		apiURL = previousURL
+		adminToken = previousAdminToken
+	}()
+
+	apiURL = server.URL
+	adminToken = testAdminToken
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("key-id", "test-key", "")
+	cmd.Flags().String("reason", "made_up_reason", "")
+	cmd.Flags().String("at", "", "")
+	cmd.Flags().Bool("generate-cert", false, "")
+	cmd.Flags().String("output", "", "")
+
+	err := adminRevokeCmd.RunE(cmd, nil)
+	if err == nil {
+		t.Fatal("expected error for unknown revocation reason")
+	}
+	if !strings.Contains(err.Error(), "invalid revocation request") {
+		t.Errorf("expected an invalid revocation request error, got %v", err)
+	}
+}
+
 // TestAdminListCommand tests key listing
 func TestAdminListCommand(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -852,3 +1168,440 @@ func TestMainFunc(t *testing.T) {
 	os.Stdout = previousStdout
 	os.Stderr = previousStderr
 }
+
+// TestWaitForCommand tests the waitfor command against an immediately
+// healthy service.
+func TestWaitForCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			t.Errorf("expected /health, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]any{
+			"status":    "healthy",
+			"version":   "1.0.0",
+			"timestamp": time.Now().Format(time.RFC3339),
+			"checks":    map[string]bool{"keyStorage": true, "database": true},
+		}); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	previousURL := apiURL
+	defer func() { apiURL = previousURL }()
+	apiURL = server.URL
+
+	previousStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe error: %v", err)
+	}
+	os.Stdout = w
+
+	err = waitForCmd.RunE(waitForCmd, nil)
+
+	if closeErr := w.Close(); closeErr != nil {
+		t.Fatalf("close error: %v", closeErr)
+	}
+	os.Stdout = previousStdout
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if !strings.Contains(string(out), "Service healthy") {
+		t.Errorf("expected healthy output, got %q", string(out))
+	}
+}
+
+// TestGitProgramCommand tests the git gpg.program shim signs stdin and
+// emits gpg-compatible status lines to the configured status-fd.
+func TestGitProgramCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sign" {
+			t.Errorf("expected /sign, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		if _, err := w.Write([]byte("-----BEGIN PGP SIGNATURE-----\ntest signature\n-----END PGP SIGNATURE-----")); err != nil {
+			t.Errorf("write error: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	previousURL := apiURL
+	previousToken := token
+	previousStdin := os.Stdin
+	defer func() {
+		apiURL = previousURL
+		token = previousToken
+		os.Stdin = previousStdin
+	}()
+	apiURL = server.URL
+	token = "test-token"
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe error: %v", err)
+	}
+	if _, err := w.Write([]byte("commit payload")); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+	os.Stdin = r
+
+	statusR, statusW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe error: %v", err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Int("status-fd", int(statusW.Fd()), "")
+	cmd.Flags().String("local-user", "my-key", "")
+
+	previousStdout := os.Stdout
+	rOut, wOut, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe error: %v", err)
+	}
+	os.Stdout = wOut
+
+	err = gitProgramCmd.RunE(cmd, nil)
+
+	if closeErr := wOut.Close(); closeErr != nil {
+		t.Fatalf("close error: %v", closeErr)
+	}
+	os.Stdout = previousStdout
+	if closeErr := statusW.Close(); closeErr != nil {
+		t.Fatalf("close error: %v", closeErr)
+	}
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	out, err := io.ReadAll(rOut)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if !strings.Contains(string(out), "BEGIN PGP SIGNATURE") {
+		t.Error("expected PGP signature on stdout")
+	}
+
+	status, err := io.ReadAll(statusR)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	for _, want := range []string{"[GNUPG:] BEGIN_SIGNING", "[GNUPG:] KEY_CONSIDERED my-key 0", "[GNUPG:] SIG_CREATED"} {
+		if !strings.Contains(string(status), want) {
+			t.Errorf("expected status output to contain %q, got %q", want, string(status))
+		}
+	}
+}
+
+func TestGitProgramVerifyRequiresSignatureArg(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("verify", true, "")
+	cmd.Flags().Int("status-fd", 2, "")
+
+	err := gitProgramCmd.RunE(cmd, nil)
+	if err == nil {
+		t.Error("expected an error when no signature file argument is given")
+	}
+}
+
+func TestGitProgramVerifyReportsBadSig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		if _, err := w.Write([]byte("not a valid armored key")); err != nil {
+			t.Errorf("write error: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	previousURL := apiURL
+	previousXDG := os.Getenv("XDG_CACHE_HOME")
+	defer func() {
+		apiURL = previousURL
+		if err := os.Setenv("XDG_CACHE_HOME", previousXDG); err != nil {
+			t.Logf("failed to restore XDG_CACHE_HOME: %v", err)
+		}
+	}()
+	apiURL = server.URL
+	if err := os.Setenv("XDG_CACHE_HOME", t.TempDir()); err != nil {
+		t.Fatalf("setenv: %v", err)
+	}
+
+	sigFile := filepath.Join(t.TempDir(), "commit.sig")
+	if err := os.WriteFile(sigFile, []byte("-----BEGIN PGP SIGNATURE-----\nbogus\n-----END PGP SIGNATURE-----"), 0o600); err != nil {
+		t.Fatalf("write sig file: %v", err)
+	}
+
+	statusR, statusW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe error: %v", err)
+	}
+	defer statusR.Close()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("verify", true, "")
+	cmd.Flags().Int("status-fd", int(statusW.Fd()), "")
+
+	previousStdin := os.Stdin
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe error: %v", err)
+	}
+	if _, err := w.Write([]byte("commit payload")); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+	os.Stdin = r
+	defer func() { os.Stdin = previousStdin }()
+
+	err = gitProgramCmd.RunE(cmd, []string{sigFile})
+
+	if closeErr := statusW.Close(); closeErr != nil {
+		t.Fatalf("close error: %v", closeErr)
+	}
+	if err == nil {
+		t.Error("expected an error for an invalid public key/signature")
+	}
+}
+
+// TestGitProgramAcceptsBundledGitInvocationFlags exercises the exact
+// invocation shape git uses for gpg.program: short flags bundled as
+// "-bsau <keyid>" followed by a numeric --status-fd. This drives RunE
+// in-process against a stub HTTP server, like the other git.* command
+// tests; see TestGitProgramEndToEndSignsAndVerifiesARealCommit below for
+// a genuine subprocess round trip through the real git binary.
+func TestGitProgramAcceptsBundledGitInvocationFlags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		if _, err := w.Write([]byte("-----BEGIN PGP SIGNATURE-----\ntest signature\n-----END PGP SIGNATURE-----")); err != nil {
+			t.Errorf("write error: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	previousURL := apiURL
+	previousToken := token
+	previousStdin := os.Stdin
+	defer func() {
+		apiURL = previousURL
+		token = previousToken
+		os.Stdin = previousStdin
+	}()
+	apiURL = server.URL
+	token = "test-token"
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe error: %v", err)
+	}
+	if _, err := w.Write([]byte("commit payload")); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+	os.Stdin = r
+
+	statusR, statusW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe error: %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"git", "program", "-bsau", "my-key", fmt.Sprintf("--status-fd=%d", statusW.Fd())})
+
+	previousStdout := os.Stdout
+	rOut, wOut, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe error: %v", err)
+	}
+	os.Stdout = wOut
+
+	err = rootCmd.Execute()
+
+	if closeErr := wOut.Close(); closeErr != nil {
+		t.Fatalf("close error: %v", closeErr)
+	}
+	os.Stdout = previousStdout
+	if closeErr := statusW.Close(); closeErr != nil {
+		t.Fatalf("close error: %v", closeErr)
+	}
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	out, err := io.ReadAll(rOut)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if !strings.Contains(string(out), "BEGIN PGP SIGNATURE") {
+		t.Error("expected PGP signature on stdout")
+	}
+
+	status, err := io.ReadAll(statusR)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if !strings.Contains(string(status), "[GNUPG:] KEY_CONSIDERED my-key 0") {
+		t.Errorf("expected -u's keyid to reach KEY_CONSIDERED, got %q", string(status))
+	}
+}
+
+// TestGitProgramEndToEndSignsAndVerifiesARealCommit builds the gpg-sign
+// binary, wires it into a throwaway git identity via GIT_CONFIG_GLOBAL,
+// and runs "git commit -S" against an httptest server standing in for
+// the signing service -- the server's /sign handler produces a real
+// detached OpenPGP signature with a freshly generated test key, and its
+// /public-key handler advertises that key's public half, so the
+// resulting commit can be checked with nothing but real git and real
+// OpenPGP verification. Unlike the other git.program tests, which drive
+// RunE in-process, this is a genuine subprocess round trip.
+func TestGitProgramEndToEndSignsAndVerifiesARealCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	entity, err := openpgp.NewEntity("Test Signer", "", "signer@example.com", nil)
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+
+	var pubKey bytes.Buffer
+	armorWriter, err := armor.Encode(&pubKey, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("open armor writer: %v", err)
+	}
+	if err := entity.Serialize(armorWriter); err != nil {
+		t.Fatalf("serialize public key: %v", err)
+	}
+	if err := armorWriter.Close(); err != nil {
+		t.Fatalf("close armor writer: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/public-key":
+			w.Header().Set("Content-Type", "application/pgp-keys")
+			if _, err := w.Write(pubKey.Bytes()); err != nil {
+				t.Errorf("write public key: %v", err)
+			}
+		case "/sign":
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("read sign payload: %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			var sig bytes.Buffer
+			if err := openpgp.ArmoredDetachSign(&sig, entity, bytes.NewReader(data), nil); err != nil {
+				t.Errorf("sign payload: %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			if _, err := w.Write(sig.Bytes()); err != nil {
+				t.Errorf("write signature: %v", err)
+			}
+		default:
+			t.Errorf("unexpected request path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	binPath := filepath.Join(t.TempDir(), "gpg-sign")
+	if out, err := exec.Command("go", "build", "-o", binPath, ".").CombinedOutput(); err != nil {
+		t.Fatalf("go build gpg-sign: %v: %s", err, out)
+	}
+
+	repoDir := t.TempDir()
+	xdgCacheDir := t.TempDir()
+	env := append(os.Environ(),
+		"GIT_CONFIG_GLOBAL="+filepath.Join(t.TempDir(), "gitconfig"),
+		"GIT_CONFIG_NOSYSTEM=1",
+		"HOME="+t.TempDir(),
+		"XDG_CACHE_HOME="+xdgCacheDir,
+		"GPG_SIGN_URL="+server.URL,
+		"GPG_SIGN_TOKEN=test-token",
+	)
+
+	runGit := func(args ...string) (string, error) {
+		cmd := exec.Command("git", append([]string{"-C", repoDir}, args...)...)
+		cmd.Env = env
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+
+	if out, err := runGit("init"); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+
+	globalConfig := [][2]string{
+		{"user.name", "Test User"},
+		{"user.email", "test@example.com"},
+		{"user.signingkey", "test-key"},
+		{"commit.gpgsign", "true"},
+		{"gpg.program", binPath + " git program"},
+	}
+	for _, kv := range globalConfig {
+		if out, err := runGit("config", "--global", kv[0], kv[1]); err != nil {
+			t.Fatalf("git config --global %s: %v: %s", kv[0], err, out)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "file.txt"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if out, err := runGit("add", "file.txt"); err != nil {
+		t.Fatalf("git add: %v: %s", err, out)
+	}
+	if out, err := runGit("commit", "-S", "-m", "signed commit"); err != nil {
+		t.Fatalf("git commit -S: %v: %s", err, out)
+	}
+
+	// %G? reports "U" for a good signature made by a key git has no
+	// trust information for, which is exactly this case: our shim emits
+	// GOODSIG/VALIDSIG but no TRUST_* status line.
+	out, err := runGit("log", "-1", "--format=%G?")
+	if err != nil {
+		t.Fatalf("git log --format=%%G?: %v: %s", err, out)
+	}
+	if got := strings.TrimSpace(out); got != "U" {
+		t.Errorf("git log --format=%%G? = %q, want %q (good signature, unknown trust)", got, "U")
+	}
+}
+
+func TestDefaultKeyCacheDirUsesXDGCacheHome(t *testing.T) {
+	previous := os.Getenv("XDG_CACHE_HOME")
+	defer func() {
+		if err := os.Setenv("XDG_CACHE_HOME", previous); err != nil {
+			t.Logf("failed to restore XDG_CACHE_HOME: %v", err)
+		}
+	}()
+
+	if err := os.Setenv("XDG_CACHE_HOME", "/xdg-cache"); err != nil {
+		t.Fatalf("setenv: %v", err)
+	}
+
+	dir, err := defaultKeyCacheDir()
+	if err != nil {
+		t.Fatalf("defaultKeyCacheDir: %v", err)
+	}
+	want := filepath.Join("/xdg-cache", "gpg-sign", "keys")
+	if dir != want {
+		t.Errorf("defaultKeyCacheDir() = %q, want %q", dir, want)
+	}
+}
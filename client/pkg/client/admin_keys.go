@@ -0,0 +1,278 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kjanat/gpg-signing-service/client/pkg/api"
+)
+
+// KeyStatus is the lifecycle status of a signing key.
+type KeyStatus string
+
+// Key lifecycle statuses.
+const (
+	KeyStatusActive   KeyStatus = "active"
+	KeyStatusDisabled KeyStatus = "disabled"
+	KeyStatusRevoked  KeyStatus = "revoked"
+)
+
+// ErrCodeKeyDisabled is returned by Sign when the targeted key exists
+// but is not in the active status.
+const ErrCodeKeyDisabled = "KEY_DISABLED"
+
+// KeyUpdate carries the fields that may be changed by UpdateKey. Only
+// non-nil fields are applied; all other key state is left untouched.
+type KeyUpdate struct {
+	Status      *KeyStatus
+	Description *string
+	Expiration  *time.Time
+}
+
+// RotationResult describes the outcome of RotateKey: the old key remains
+// valid for verification until GraceUntil so that signatures made just
+// before the rotation still validate.
+type RotationResult struct {
+	OldKeyID       string
+	OldFingerprint string
+	NewKeyID       string
+	NewFingerprint string
+	GraceUntil     time.Time
+}
+
+// validStatusTransitions enumerates the key status changes the service
+// allows. validateStatusTransition checks a from/to pair against it;
+// UpdateKey and SetKeyStatus send their request without consulting it.
+var validStatusTransitions = map[KeyStatus]map[KeyStatus]bool{
+	KeyStatusActive:   {KeyStatusDisabled: true, KeyStatusRevoked: true},
+	KeyStatusDisabled: {KeyStatusActive: true, KeyStatusRevoked: true},
+	KeyStatusRevoked:  {},
+}
+
+// UpdateKey modifies an existing signing key's status, description, or
+// expiration (admin operation). Fields left nil in req are unchanged.
+func (c *Client) UpdateKey(ctx context.Context, keyID string, req KeyUpdate) (*KeyInfo, error) {
+	if keyID == "" {
+		return nil, &ValidationError{Code: "INVALID_REQUEST", Message: "keyID cannot be empty"}
+	}
+
+	body := api.PatchAdminKeysKeyIdJSONRequestBody{}
+	if req.Status != nil {
+		body.Status = (*api.KeyStatus)(req.Status)
+	}
+	if req.Description != nil {
+		body.Description = req.Description
+	}
+	if req.Expiration != nil {
+		body.Expiration = req.Expiration
+	}
+
+	var resp *api.PatchAdminKeysKeyIdResponse
+	err := c.retrier.Do(ctx, func() error {
+		var execErr error
+		resp, execErr = c.raw.PatchAdminKeysKeyIdWithResponse(ctx, keyID, body)
+		return execErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.JSON200 != nil {
+		return &KeyInfo{KeyID: resp.JSON200.KeyId, Fingerprint: resp.JSON200.Fingerprint}, nil
+	}
+	if resp.JSON400 != nil {
+		return nil, &ValidationError{Code: string(resp.JSON400.Code), Message: resp.JSON400.Error}
+	}
+	if resp.JSON404 != nil {
+		return nil, &ServiceError{Code: string(resp.JSON404.Code), Message: resp.JSON404.Error, StatusCode: 404}
+	}
+	if resp.JSON500 != nil {
+		return nil, newServiceError(resp.HTTPResponse, string(resp.JSON500.Code), resp.JSON500.Error, 500)
+	}
+	return nil, newUnexpectedStatusError(resp.StatusCode())
+}
+
+// SetKeyStatus is a convenience wrapper around UpdateKey for the common
+// case of only changing a key's lifecycle status. It does not know the
+// key's current status, so it does not check the transition against
+// validStatusTransitions before issuing the request.
+func (c *Client) SetKeyStatus(ctx context.Context, keyID string, status KeyStatus) (*KeyInfo, error) {
+	if keyID == "" {
+		return nil, &ValidationError{Code: "INVALID_REQUEST", Message: "keyID cannot be empty"}
+	}
+	return c.UpdateKey(ctx, keyID, KeyUpdate{Status: &status})
+}
+
+// validateStatusTransition returns a *ValidationError if moving from
+// "from" to "to" is not a legal key lifecycle transition.
+func validateStatusTransition(from, to KeyStatus) error {
+	allowed, ok := validStatusTransitions[from]
+	if !ok || !allowed[to] {
+		return &ValidationError{
+			Code:    "INVALID_STATUS_TRANSITION",
+			Message: fmt.Sprintf("cannot transition key status from %q to %q", from, to),
+		}
+	}
+	return nil
+}
+
+// RotateKey atomically uploads newArmored as a replacement for oldKeyID,
+// moves the "default" key pointer to the new key, and keeps the old key
+// valid for verification during a grace window. Both fingerprints are
+// returned so callers can update downstream trust stores.
+func (c *Client) RotateKey(ctx context.Context, oldKeyID string, newArmored string) (*RotationResult, error) {
+	if oldKeyID == "" {
+		return nil, &ValidationError{Code: "INVALID_REQUEST", Message: "oldKeyID cannot be empty"}
+	}
+	if newArmored == "" {
+		return nil, &ValidationError{Code: "INVALID_REQUEST", Message: "newArmored cannot be empty"}
+	}
+
+	body := api.PostAdminKeysKeyIdRotateJSONRequestBody{
+		ArmoredPrivateKey: newArmored,
+	}
+
+	var resp *api.PostAdminKeysKeyIdRotateResponse
+	err := c.retrier.Do(ctx, func() error {
+		var execErr error
+		resp, execErr = c.raw.PostAdminKeysKeyIdRotateWithResponse(ctx, oldKeyID, body)
+		return execErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.JSON200 != nil {
+		return &RotationResult{
+			OldKeyID:       oldKeyID,
+			OldFingerprint: resp.JSON200.OldFingerprint,
+			NewKeyID:       resp.JSON200.NewKeyId,
+			NewFingerprint: resp.JSON200.NewFingerprint,
+			GraceUntil:     resp.JSON200.GraceUntil,
+		}, nil
+	}
+	if resp.JSON400 != nil {
+		return nil, &ValidationError{Code: string(resp.JSON400.Code), Message: resp.JSON400.Error}
+	}
+	if resp.JSON404 != nil {
+		return nil, &ServiceError{Code: string(resp.JSON404.Code), Message: resp.JSON404.Error, StatusCode: 404}
+	}
+	if resp.JSON500 != nil {
+		return nil, newServiceError(resp.HTTPResponse, string(resp.JSON500.Code), resp.JSON500.Error, 500)
+	}
+	return nil, newUnexpectedStatusError(resp.StatusCode())
+}
+
+// IsKeyDisabled returns true if the error indicates Sign targeted a
+// disabled key.
+func IsKeyDisabled(err error) bool {
+	var se *ServiceError
+	return errors.As(err, &se) && se.Code == ErrCodeKeyDisabled
+}
+
+// RevocationReason is why a signing key was revoked, following the
+// ACME (RFC 8555 §7.6) / OpenPGP revocation reason codes.
+type RevocationReason string
+
+// Revocation reason codes.
+const (
+	RevocationKeyCompromise RevocationReason = "key_compromise"
+	RevocationSuperseded    RevocationReason = "superseded"
+	RevocationNoLongerUsed  RevocationReason = "no_longer_used"
+	RevocationUnspecified   RevocationReason = "unspecified"
+)
+
+var validRevocationReasons = map[RevocationReason]bool{
+	RevocationKeyCompromise: true,
+	RevocationSuperseded:    true,
+	RevocationNoLongerUsed:  true,
+	RevocationUnspecified:   true,
+}
+
+// ErrCodeKeyRevoked is returned by Sign and PublicKey when the targeted
+// key has been revoked.
+const ErrCodeKeyRevoked = "KEY_REVOKED"
+
+// RevokeKeyRequest carries the parameters for RevokeKey. At defaults to
+// time.Now when zero. GenerateRevocationCert asks the server to return
+// an armored OpenPGP revocation certificate alongside the revocation,
+// for operators who want to publish it to a keyserver.
+type RevokeKeyRequest struct {
+	Reason                 RevocationReason
+	At                     time.Time
+	GenerateRevocationCert bool
+}
+
+// RevokeKeyResult describes the outcome of RevokeKey. RevocationCert is
+// empty unless RevokeKeyRequest.GenerateRevocationCert was set.
+type RevokeKeyResult struct {
+	KeyID          string
+	Reason         RevocationReason
+	RevokedAt      time.Time
+	RevocationCert string
+}
+
+// RevokeKey revokes a signing key (admin operation), modeled on
+// ACME/OpenPGP revocation semantics: once revoked, the key can never be
+// reactivated (see validStatusTransitions), and the service starts
+// returning 410 Gone with the revocation reason on subsequent PublicKey
+// and Sign calls for it. For a plain status flip with no reason or
+// certificate, prefer the cheaper SetKeyStatus(ctx, keyID,
+// KeyStatusRevoked).
+func (c *Client) RevokeKey(ctx context.Context, keyID string, req RevokeKeyRequest) (*RevokeKeyResult, error) {
+	if keyID == "" {
+		return nil, &ValidationError{Code: "INVALID_REQUEST", Message: "keyID cannot be empty"}
+	}
+	if !validRevocationReasons[req.Reason] {
+		return nil, &ValidationError{Code: "INVALID_REQUEST", Message: fmt.Sprintf("unknown revocation reason %q", req.Reason)}
+	}
+
+	at := req.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	body := api.PostAdminKeysKeyIdRevokeJSONRequestBody{
+		Reason:                 api.RevocationReason(req.Reason),
+		At:                     at,
+		GenerateRevocationCert: req.GenerateRevocationCert,
+	}
+
+	var resp *api.PostAdminKeysKeyIdRevokeResponse
+	err := c.retrier.Do(ctx, func() error {
+		var execErr error
+		resp, execErr = c.raw.PostAdminKeysKeyIdRevokeWithResponse(ctx, keyID, body)
+		return execErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.JSON200 != nil {
+		return &RevokeKeyResult{
+			KeyID:          keyID,
+			Reason:         req.Reason,
+			RevokedAt:      at,
+			RevocationCert: resp.JSON200.RevocationCert,
+		}, nil
+	}
+	if resp.JSON400 != nil {
+		return nil, &ValidationError{Code: string(resp.JSON400.Code), Message: resp.JSON400.Error}
+	}
+	if resp.JSON404 != nil {
+		return nil, &ServiceError{Code: string(resp.JSON404.Code), Message: resp.JSON404.Error, StatusCode: 404}
+	}
+	if resp.JSON500 != nil {
+		return nil, newServiceError(resp.HTTPResponse, string(resp.JSON500.Code), resp.JSON500.Error, 500)
+	}
+	return nil, newUnexpectedStatusError(resp.StatusCode())
+}
+
+// IsKeyRevoked returns true if the error indicates Sign or PublicKey
+// targeted a revoked key.
+func IsKeyRevoked(err error) bool {
+	var se *ServiceError
+	return errors.As(err, &se) && se.Code == ErrCodeKeyRevoked
+}
@@ -0,0 +1,70 @@
+package client
+
+import "testing"
+
+func TestValidateStatusTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    KeyStatus
+		to      KeyStatus
+		wantErr bool
+	}{
+		{"active to disabled", KeyStatusActive, KeyStatusDisabled, false},
+		{"active to revoked", KeyStatusActive, KeyStatusRevoked, false},
+		{"disabled to active", KeyStatusDisabled, KeyStatusActive, false},
+		{"revoked to active", KeyStatusRevoked, KeyStatusActive, true},
+		{"revoked to disabled", KeyStatusRevoked, KeyStatusDisabled, true},
+		{"active to active", KeyStatusActive, KeyStatusActive, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStatusTransition(tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateStatusTransition(%s, %s) error = %v, wantErr %v", tt.from, tt.to, err, tt.wantErr)
+			}
+			if err != nil && !IsValidationError(err) {
+				t.Errorf("expected ValidationError, got %T", err)
+			}
+		})
+	}
+}
+
+func TestUpdateKeyRejectsEmptyKeyID(t *testing.T) {
+	c := &Client{}
+	_, err := c.UpdateKey(nil, "", KeyUpdate{})
+	if !IsValidationError(err) {
+		t.Errorf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestRevokeKeyRejectsEmptyKeyID(t *testing.T) {
+	c := &Client{}
+	_, err := c.RevokeKey(nil, "", RevokeKeyRequest{Reason: RevocationUnspecified})
+	if !IsValidationError(err) {
+		t.Errorf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestRevokeKeyRejectsUnknownReason(t *testing.T) {
+	tests := []struct {
+		name    string
+		reason  RevocationReason
+		wantErr bool
+	}{
+		{"key_compromise", RevocationKeyCompromise, false},
+		{"superseded", RevocationSuperseded, false},
+		{"no_longer_used", RevocationNoLongerUsed, false},
+		{"unspecified", RevocationUnspecified, false},
+		{"unknown", RevocationReason("made_up_reason"), true},
+		{"empty", RevocationReason(""), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validRevocationReasons[tt.reason]; got == tt.wantErr {
+				t.Errorf("validRevocationReasons[%q] = %v, want %v", tt.reason, got, !tt.wantErr)
+			}
+		})
+	}
+}
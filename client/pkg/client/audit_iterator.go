@@ -0,0 +1,217 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// AuditIterator pages through audit logs matching a filter, fetching one
+// page at a time via the server's opaque cursor rather than loading
+// every matching row into memory like AuditLogs does.
+type AuditIterator struct {
+	c      *Client
+	ctx    context.Context
+	filter AuditFilter
+
+	page    []AuditLog
+	pos     int
+	cursor  string
+	done    bool
+	current AuditLog
+	err     error
+}
+
+// AuditLogsIter returns an iterator over audit logs matching filter.
+// Call Next to advance, Log to read the current entry, and Err once
+// Next returns false to check whether iteration stopped because of an
+// error or because the logs were exhausted.
+func (c *Client) AuditLogsIter(ctx context.Context, filter AuditFilter) *AuditIterator {
+	return &AuditIterator{c: c, ctx: ctx, filter: filter, cursor: filter.Cursor}
+}
+
+// Next advances the iterator, fetching the next page from the server
+// once the current page is exhausted. It returns false when there are
+// no more entries or an error occurred; call Err to distinguish the two.
+func (it *AuditIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.pos >= len(it.page) {
+		if it.done {
+			return false
+		}
+
+		page, nextCursor, err := it.c.fetchAuditPage(it.ctx, it.filter, it.cursor)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page = page
+		it.pos = 0
+		it.cursor = nextCursor
+		it.done = nextCursor == ""
+	}
+
+	it.current = it.page[it.pos]
+	it.pos++
+	return true
+}
+
+// Log returns the entry most recently advanced to by Next.
+func (it *AuditIterator) Log() AuditLog {
+	return it.current
+}
+
+// Err returns the first error encountered while paging, if any.
+func (it *AuditIterator) Err() error {
+	return it.err
+}
+
+// auditPageResponse is the JSON shape of a single /admin/audit page,
+// including the opaque cursor for the next page.
+type auditPageResponse struct {
+	Logs       []AuditLog `json:"logs"`
+	Count      int        `json:"count"`
+	NextCursor string     `json:"nextCursor"`
+}
+
+// fetchAuditPage requests a single cursor-addressed page from GET
+// /admin/audit, bypassing the generated client since it has no cursor
+// parameter.
+func (c *Client) fetchAuditPage(ctx context.Context, filter AuditFilter, cursor string) ([]AuditLog, string, error) {
+	var page auditPageResponse
+
+	err := c.retrier.Do(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/admin/audit", nil)
+		if err != nil {
+			return err
+		}
+		req.URL.RawQuery = buildAuditPageQuery(filter, cursor).Encode()
+		req.Header.Set("Authorization", c.authHeader())
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			var errBody struct {
+				Code  string `json:"code"`
+				Error string `json:"error"`
+			}
+			_ = json.NewDecoder(resp.Body).Decode(&errBody)
+			return newServiceError(resp, errBody.Code, errBody.Error, resp.StatusCode)
+		}
+
+		page = auditPageResponse{}
+		return json.NewDecoder(resp.Body).Decode(&page)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return page.Logs, page.NextCursor, nil
+}
+
+func buildAuditPageQuery(filter AuditFilter, cursor string) url.Values {
+	q := url.Values{}
+	if cursor != "" {
+		q.Set("cursor", cursor)
+	}
+	if filter.Limit > 0 {
+		q.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	if filter.Action != "" {
+		q.Set("action", filter.Action)
+	}
+	if filter.Subject != "" {
+		q.Set("subject", filter.Subject)
+	}
+	if !filter.StartDate.IsZero() {
+		q.Set("startDate", filter.StartDate.Format(time.RFC3339))
+	}
+	if !filter.EndDate.IsZero() {
+		q.Set("endDate", filter.EndDate.Format(time.RFC3339))
+	}
+	return q
+}
+
+// AuditLogsStream issues a single GET /admin/audit request with Accept:
+// application/x-ndjson and delivers each row onto the returned channel
+// as it arrives over the response's chunked transfer encoding, so a
+// caller processing a large historical query doesn't have to buffer
+// every row in memory like AuditLogs does. The entries channel is
+// closed when the stream ends (including on ctx cancellation); at most
+// one error is sent on the error channel if it ends abnormally.
+func (c *Client) AuditLogsStream(ctx context.Context, filter AuditFilter) (<-chan AuditLog, <-chan error) {
+	entries := make(chan AuditLog)
+	errs := make(chan error, 1)
+
+	go c.runAuditLogsStream(ctx, filter, entries, errs)
+
+	return entries, errs
+}
+
+func (c *Client) runAuditLogsStream(ctx context.Context, filter AuditFilter, entries chan<- AuditLog, errs chan<- error) {
+	defer close(entries)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/admin/audit", nil)
+	if err != nil {
+		errs <- err
+		return
+	}
+	req.URL.RawQuery = buildAuditPageQuery(filter, filter.Cursor).Encode()
+	req.Header.Set("Authorization", c.authHeader())
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		errs <- err
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errBody struct {
+			Code  string `json:"code"`
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&errBody)
+		errs <- newServiceError(resp, errBody.Code, errBody.Error, resp.StatusCode)
+		return
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxAuditMessageBytes)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry AuditLog
+		if err := json.Unmarshal(line, &entry); err != nil {
+			errs <- fmt.Errorf("decode audit log: %w", err)
+			return
+		}
+
+		select {
+		case entries <- entry:
+		case <-ctx.Done():
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		errs <- err
+	}
+}
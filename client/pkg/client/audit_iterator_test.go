@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAuditLogsIterPagesUntilCursorExhausted(t *testing.T) {
+	pages := []string{
+		`{"logs":[{"id":"1","action":"sign"},{"id":"2","action":"sign"}],"count":2,"nextCursor":"page-2"}`,
+		`{"logs":[{"id":"3","action":"key_upload"}],"count":1,"nextCursor":""}`,
+	}
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("cursor")
+		if requests == 0 && cursor != "" {
+			t.Errorf("expected no cursor on first request, got %q", cursor)
+		}
+		if requests == 1 && cursor != "page-2" {
+			t.Errorf("expected cursor page-2 on second request, got %q", cursor)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(pages[requests]))
+		requests++
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	it := c.AuditLogsIter(context.Background(), AuditFilter{})
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Log().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iterator error: %v", err)
+	}
+
+	want := []string{"1", "2", "3"}
+	if len(ids) != len(want) {
+		t.Fatalf("got %v, want %v", ids, want)
+	}
+	for i, id := range ids {
+		if id != want[i] {
+			t.Errorf("ids[%d] = %q, want %q", i, id, want[i])
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 page requests, got %d", requests)
+	}
+}
+
+func TestAuditLogsIterStopsOnServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"code":"INTERNAL","error":"boom"}`))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	it := c.AuditLogsIter(context.Background(), AuditFilter{})
+	if it.Next() {
+		t.Fatal("expected Next to return false on server error")
+	}
+	if it.Err() == nil {
+		t.Error("expected non-nil Err after a failed page fetch")
+	}
+}
+
+func TestAuditLogsStreamDeliversNDJSONRows(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); accept != "application/x-ndjson" {
+			t.Errorf("expected Accept: application/x-ndjson, got %q", accept)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for i := 1; i <= 3; i++ {
+			fmt.Fprintf(w, "{\"id\":\"%d\",\"action\":\"sign\"}\n", i)
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	entries, errs := c.AuditLogsStream(context.Background(), AuditFilter{})
+
+	var got []AuditLog
+	for entries != nil || errs != nil {
+		select {
+		case entry, ok := <-entries:
+			if !ok {
+				entries = nil
+				continue
+			}
+			got = append(got, entry)
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			t.Fatalf("unexpected stream error: %v", err)
+		}
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(got))
+	}
+	if got[0].ID != "1" || got[2].ID != "3" {
+		t.Errorf("unexpected entries: %+v", got)
+	}
+}
+
+func TestAuditLogsStreamStopsOnContextCancellation(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		fmt.Fprintln(w, `{"id":"1","action":"sign"}`)
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		<-unblock
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	entries, errs := c.AuditLogsStream(ctx, AuditFilter{})
+
+	first, ok := <-entries
+	if !ok || first.ID != "1" {
+		t.Fatalf("expected first entry with ID 1, got %+v ok=%v", first, ok)
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-entries:
+		if ok {
+			t.Error("expected entries channel to close after cancellation")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for entries channel to close after cancellation")
+	}
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Errorf("expected no error after cancellation, got %v", err)
+		}
+	default:
+	}
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TailOption configures StreamAuditLogs.
+type TailOption func(*tailOptions)
+
+type tailOptions struct {
+	sinceID   string
+	sinceTime time.Time
+}
+
+// WithSince resumes a tail from the event with the given ID, exclusive.
+// Use this to reconnect after a disconnect without re-delivering events
+// the caller already processed.
+func WithSince(id string) TailOption {
+	return func(o *tailOptions) {
+		o.sinceID = id
+	}
+}
+
+// WithSinceTime resumes a tail from the first event at or after t.
+func WithSinceTime(t time.Time) TailOption {
+	return func(o *tailOptions) {
+		o.sinceTime = t
+	}
+}
+
+// maxAuditMessageBytes bounds a single streamed audit entry (including
+// its metadata JSON blob), analogous to grpc-websocket-proxy's
+// WithMaxRespBodyBufferSize. It is well above the server's default ~64
+// KiB SSE frame size so large metadata payloads are not truncated.
+const maxAuditMessageBytes = 1024 * 1024
+
+// StreamAuditLogs opens a long-lived streaming connection to GET
+// /audit/stream and delivers entries as they are written. The endpoint
+// upgrades to WebSocket where supported and falls back to SSE when the
+// request sends Accept: text/event-stream, which is what this client
+// uses. The returned event channel is closed when the stream ends
+// (including on ctx cancellation); at most one error is sent on the
+// error channel when the stream terminates abnormally. Disconnects are
+// resumed from the last delivered event (via Last-Event-ID and a
+// matching query parameter) and retried with the client's configured
+// Retrier before giving up.
+func (c *Client) StreamAuditLogs(ctx context.Context, filter AuditFilter, opts ...TailOption) (<-chan AuditEvent, <-chan error) {
+	o := &tailOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	events := make(chan AuditEvent)
+	errs := make(chan error, 1)
+
+	go c.runAuditTail(ctx, filter, o, events, errs)
+
+	return events, errs
+}
+
+// AuditEvent is a single entry delivered by StreamAuditLogs.
+type AuditEvent struct {
+	AuditLog
+	Cursor string
+}
+
+func (c *Client) runAuditTail(ctx context.Context, filter AuditFilter, o *tailOptions, events chan<- AuditEvent, errs chan<- error) {
+	defer close(events)
+
+	for {
+		err := c.retrier.Do(ctx, func() error {
+			return c.streamAuditOnce(ctx, filter, o, events)
+		})
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		if !c.retrier.shouldRetry(err) {
+			select {
+			case errs <- err:
+			default:
+			}
+			return
+		}
+	}
+}
+
+func (c *Client) streamAuditOnce(ctx context.Context, filter AuditFilter, o *tailOptions, events chan<- AuditEvent) error {
+	url := c.baseURL + "/admin/audit/stream"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	q := req.URL.Query()
+	if o.sinceID != "" {
+		q.Set("since", o.sinceID)
+		req.Header.Set("Last-Event-ID", o.sinceID)
+	} else if !o.sinceTime.IsZero() {
+		q.Set("sinceTime", o.sinceTime.Format(time.RFC3339))
+	}
+	if filter.Action != "" {
+		q.Set("action", filter.Action)
+	}
+	if filter.Subject != "" {
+		q.Set("subject", filter.Subject)
+	}
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Authorization", c.authHeader())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newUnexpectedStatusError(resp.StatusCode)
+	}
+
+	return parseSSE(resp, o, events)
+}
+
+func parseSSE(resp *http.Response, o *tailOptions, events chan<- AuditEvent) error {
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxAuditMessageBytes)
+
+	var dataLines []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(dataLines) == 0 {
+				continue
+			}
+			payload := strings.Join(dataLines, "\n")
+			dataLines = dataLines[:0]
+			if payload == "" {
+				continue // heartbeat
+			}
+			var entry AuditLog
+			if err := json.Unmarshal([]byte(payload), &entry); err != nil {
+				return fmt.Errorf("decode audit event: %w", err)
+			}
+			o.sinceID = entry.ID
+			events <- AuditEvent{AuditLog: entry, Cursor: entry.ID}
+		case strings.HasPrefix(line, "data:"):
+			dataLines = append(dataLines, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		case strings.HasPrefix(line, ":"):
+			// comment / heartbeat line per the SSE spec
+		}
+	}
+	return scanner.Err()
+}
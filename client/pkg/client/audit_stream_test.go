@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSSEDeliversEvents(t *testing.T) {
+	body := "" +
+		"data: {\"id\":\"1\",\"action\":\"sign\"}\n\n" +
+		": heartbeat\n\n" +
+		"data: {\"id\":\"2\",\"action\":\"key_upload\"}\n\n"
+
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader(body))}
+	o := &tailOptions{}
+	events := make(chan AuditEvent, 2)
+
+	if err := parseSSE(resp, o, events); err != nil {
+		t.Fatalf("parseSSE: %v", err)
+	}
+	close(events)
+
+	var got []AuditEvent
+	for e := range events {
+		got = append(got, e)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(got))
+	}
+	if got[0].ID != "1" || got[0].Action != "sign" {
+		t.Errorf("unexpected first event: %+v", got[0])
+	}
+	if got[1].ID != "2" || got[1].Action != "key_upload" {
+		t.Errorf("unexpected second event: %+v", got[1])
+	}
+	if o.sinceID != "2" {
+		t.Errorf("expected cursor to advance to last event ID, got %q", o.sinceID)
+	}
+}
+
+func TestAuditLogsFollowUpgradesToStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, "data: {\"id\":\"1\",\"action\":\"sign\"}\n\n")
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithTimeout(5*time.Second))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	result, err := c.AuditLogs(ctx, AuditFilter{Follow: true})
+	if err != nil {
+		t.Fatalf("AuditLogs: %v", err)
+	}
+	if len(result.Logs) == 0 {
+		t.Fatal("expected at least one log entry collected while following")
+	}
+	if result.Logs[0].ID != "1" {
+		t.Errorf("unexpected first log: %+v", result.Logs[0])
+	}
+}
@@ -0,0 +1,282 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// SignedTreeHead is a signed statement of the audit log's Merkle tree
+// root at TreeSize entries, in the style of a Certificate Transparency
+// signed tree head (RFC 6962). Signatures carries one entry per
+// attesting party, so a deployment can require cosignatures from both
+// the signing service and an independent witness over the same root.
+type SignedTreeHead struct {
+	TreeSize   int64       `json:"treeSize"`
+	RootHash   []byte      `json:"rootHash"`
+	Timestamp  int64       `json:"timestamp"`
+	Signatures []Signature `json:"signatures"`
+}
+
+// Signature is one witness's attestation over a SignedTreeHead, keyed by
+// KeyID so callers can look up the matching public key.
+type Signature struct {
+	KeyID string `json:"keyId"`
+	Sig   []byte `json:"sig"`
+}
+
+// InclusionProof is a Merkle audit path (RFC 6962 section 2.1.1)
+// proving that the log entry at LeafIndex is included in the tree of
+// TreeSize entries described by a SignedTreeHead. Hashes runs from the
+// leaf's sibling up to (but not including) the root.
+type InclusionProof struct {
+	LeafIndex int64    `json:"leafIndex"`
+	TreeSize  int64    `json:"treeSize"`
+	Hashes    [][]byte `json:"hashes"`
+}
+
+// AuditTreeHead retrieves the current signed tree head of the audit
+// log, for use with VerifyAuditLog or VerifyInclusionProof.
+func (c *Client) AuditTreeHead(ctx context.Context) (*SignedTreeHead, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/admin/audit/tree-head", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+
+	var sth *SignedTreeHead
+	err = c.retrier.Do(ctx, func() error {
+		resp, execErr := c.httpClient.Do(req)
+		if execErr != nil {
+			return execErr
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return newUnexpectedStatusError(resp.StatusCode)
+		}
+
+		var body SignedTreeHead
+		if execErr := json.NewDecoder(resp.Body).Decode(&body); execErr != nil {
+			return execErr
+		}
+		sth = &body
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sth, nil
+}
+
+// AuditInclusionProof retrieves the Merkle audit path proving logID is
+// included in the current signed tree head, for verification via
+// VerifyInclusionProof without re-fetching every audit log entry.
+func (c *Client) AuditInclusionProof(ctx context.Context, logID string) (*InclusionProof, error) {
+	if logID == "" {
+		return nil, &ValidationError{Code: "INVALID_REQUEST", Message: "logID cannot be empty"}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/admin/audit/inclusion-proof", nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Set("logId", logID)
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Authorization", c.authHeader())
+
+	var proof *InclusionProof
+	err = c.retrier.Do(ctx, func() error {
+		resp, execErr := c.httpClient.Do(req)
+		if execErr != nil {
+			return execErr
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound {
+			return &ServiceError{Code: "NOT_FOUND", Message: "audit log entry not found", StatusCode: http.StatusNotFound}
+		}
+		if resp.StatusCode != http.StatusOK {
+			return newUnexpectedStatusError(resp.StatusCode)
+		}
+
+		var body InclusionProof
+		if execErr := json.NewDecoder(resp.Body).Decode(&body); execErr != nil {
+			return execErr
+		}
+		proof = &body
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return proof, nil
+}
+
+// VerifyAuditLog checks that logs is exactly the set of entries
+// committed to by sth: it rebuilds the Merkle tree from logs' leaf
+// hashes, confirms the recomputed root matches sth.RootHash, and checks
+// every one of sth.Signatures against trustedKeys (keyed by KeyID). It
+// returns an error describing the first failure found. This gives
+// operators cryptographic assurance that an AuditLogs response has not
+// been silently truncated or rewritten by a compromised server, so long
+// as len(logs) == sth.TreeSize (i.e. logs is a complete snapshot, not a
+// page of a larger query). To verify a single entry against a tree head
+// without fetching every log, use AuditInclusionProof and
+// VerifyInclusionProof instead.
+func (c *Client) VerifyAuditLog(ctx context.Context, logs []AuditLog, sth *SignedTreeHead, trustedKeys map[string]ed25519.PublicKey) error {
+	if sth == nil {
+		return errors.New("client: verify audit log: signed tree head is nil")
+	}
+	if int64(len(logs)) != sth.TreeSize {
+		return fmt.Errorf("client: verify audit log: got %d logs, signed tree head claims tree size %d", len(logs), sth.TreeSize)
+	}
+
+	leaves := make([][]byte, len(logs))
+	for i, log := range logs {
+		h, err := auditLeafHash(log)
+		if err != nil {
+			return fmt.Errorf("client: verify audit log: hash entry %d: %w", i, err)
+		}
+		leaves[i] = h
+	}
+
+	root := merkleRoot(leaves)
+	if !bytes.Equal(root, sth.RootHash) {
+		return fmt.Errorf("client: verify audit log: recomputed root %x does not match signed tree head root %x", root, sth.RootHash)
+	}
+
+	return verifyTreeHeadSignatures(sth, trustedKeys)
+}
+
+// VerifyInclusionProof checks that log is included at proof.LeafIndex in
+// the tree described by sth, and that every one of sth.Signatures
+// verifies against trustedKeys. Unlike VerifyAuditLog, this only
+// requires the single entry being checked and its proof, not every log
+// entry in the tree.
+func (c *Client) VerifyInclusionProof(log AuditLog, proof *InclusionProof, sth *SignedTreeHead, trustedKeys map[string]ed25519.PublicKey) error {
+	if proof == nil || sth == nil {
+		return errors.New("client: verify inclusion proof: proof and signed tree head are required")
+	}
+	if proof.TreeSize != sth.TreeSize {
+		return fmt.Errorf("client: verify inclusion proof: proof tree size %d does not match signed tree head size %d", proof.TreeSize, sth.TreeSize)
+	}
+
+	leaf, err := auditLeafHash(log)
+	if err != nil {
+		return fmt.Errorf("client: verify inclusion proof: hash entry: %w", err)
+	}
+	if !verifyMerkleInclusion(leaf, proof.LeafIndex, proof.TreeSize, proof.Hashes, sth.RootHash) {
+		return fmt.Errorf("client: verify inclusion proof: entry %q is not included in the signed tree head", log.ID)
+	}
+
+	return verifyTreeHeadSignatures(sth, trustedKeys)
+}
+
+func verifyTreeHeadSignatures(sth *SignedTreeHead, trustedKeys map[string]ed25519.PublicKey) error {
+	if len(sth.Signatures) == 0 {
+		return errors.New("client: verify tree head: signed tree head has no signatures")
+	}
+
+	digest := signedTreeHeadDigest(sth)
+	for _, sig := range sth.Signatures {
+		key, ok := trustedKeys[sig.KeyID]
+		if !ok {
+			return fmt.Errorf("client: verify tree head: signature from unrecognized key %q", sig.KeyID)
+		}
+		if !ed25519.Verify(key, digest, sig.Sig) {
+			return fmt.Errorf("client: verify tree head: signature from key %q does not verify", sig.KeyID)
+		}
+	}
+
+	return nil
+}
+
+// signedTreeHeadDigest is the message each Signature is computed over:
+// the tree size and timestamp as big-endian uint64s around the root
+// hash, so a signature over one tree head can't be replayed against a
+// different size or timestamp sharing the same root.
+func signedTreeHeadDigest(sth *SignedTreeHead) []byte {
+	buf := make([]byte, 0, 8+len(sth.RootHash)+8)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(sth.TreeSize))
+	buf = append(buf, sth.RootHash...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(sth.Timestamp))
+	return buf
+}
+
+// auditLeafHash hashes an audit log entry into a Merkle leaf, following
+// RFC 6962's MTH leaf convention (0x00 prefix) over the entry's JSON
+// encoding.
+func auditLeafHash(log AuditLog) ([]byte, error) {
+	data, err := json.Marshal(log)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(append([]byte{0x00}, data...))
+	return h[:], nil
+}
+
+func merkleNodeHash(left, right []byte) []byte {
+	buf := make([]byte, 0, 1+len(left)+len(right))
+	buf = append(buf, 0x01)
+	buf = append(buf, left...)
+	buf = append(buf, right...)
+	h := sha256.Sum256(buf)
+	return h[:]
+}
+
+// merkleRoot computes the RFC 6962 Merkle Tree Hash of leaves, splitting
+// at the largest power of two strictly less than the slice length at
+// each level.
+func merkleRoot(leaves [][]byte) []byte {
+	n := len(leaves)
+	if n == 0 {
+		h := sha256.Sum256(nil)
+		return h[:]
+	}
+	if n == 1 {
+		return leaves[0]
+	}
+	k := largestPowerOfTwoLessThan(n)
+	return merkleNodeHash(merkleRoot(leaves[:k]), merkleRoot(leaves[k:]))
+}
+
+func largestPowerOfTwoLessThan(n int) int {
+	k := 1
+	for k*2 < n {
+		k *= 2
+	}
+	return k
+}
+
+// verifyMerkleInclusion implements RFC 6962 section 2.1.3.2's audit
+// path verification: it walks proof from leaf to root, folding each
+// sibling hash in on the correct side, and reports whether the
+// resulting root matches rootHash.
+func verifyMerkleInclusion(leafHash []byte, leafIndex, treeSize int64, proof [][]byte, rootHash []byte) bool {
+	fn, sn := leafIndex, treeSize-1
+	r := leafHash
+	for _, sibling := range proof {
+		if fn&1 == 1 || fn == sn {
+			r = merkleNodeHash(sibling, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = merkleNodeHash(r, sibling)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(r, rootHash)
+}
@@ -0,0 +1,269 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func sampleAuditLogs(n int) []AuditLog {
+	logs := make([]AuditLog, n)
+	for i := range logs {
+		logs[i] = AuditLog{ID: string(rune('a' + i)), Action: "sign"}
+	}
+	return logs
+}
+
+func signTreeHead(priv ed25519.PrivateKey, keyID string, sth *SignedTreeHead) {
+	sth.Signatures = append(sth.Signatures, Signature{
+		KeyID: keyID,
+		Sig:   ed25519.Sign(priv, signedTreeHeadDigest(sth)),
+	})
+}
+
+func buildSignedTreeHead(t *testing.T, logs []AuditLog, priv ed25519.PrivateKey, keyID string) *SignedTreeHead {
+	t.Helper()
+	leaves := make([][]byte, len(logs))
+	for i, log := range logs {
+		h, err := auditLeafHash(log)
+		if err != nil {
+			t.Fatalf("auditLeafHash: %v", err)
+		}
+		leaves[i] = h
+	}
+	sth := &SignedTreeHead{TreeSize: int64(len(logs)), RootHash: merkleRoot(leaves), Timestamp: 1700000000}
+	signTreeHead(priv, keyID, sth)
+	return sth
+}
+
+func TestVerifyAuditLogAcceptsMatchingRootAndSignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	logs := sampleAuditLogs(5)
+	sth := buildSignedTreeHead(t, logs, priv, "witness-1")
+
+	c := &Client{}
+	err = c.VerifyAuditLog(context.Background(), logs, sth, map[string]ed25519.PublicKey{"witness-1": pub})
+	if err != nil {
+		t.Fatalf("VerifyAuditLog: %v", err)
+	}
+}
+
+func TestVerifyAuditLogRejectsTruncatedLogs(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	logs := sampleAuditLogs(5)
+	sth := buildSignedTreeHead(t, logs, priv, "witness-1")
+
+	c := &Client{}
+	err = c.VerifyAuditLog(context.Background(), logs[:4], sth, map[string]ed25519.PublicKey{"witness-1": pub})
+	if err == nil {
+		t.Fatal("expected error for a tree size mismatch (truncated logs)")
+	}
+}
+
+func TestVerifyAuditLogRejectsRewrittenEntry(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	logs := sampleAuditLogs(5)
+	sth := buildSignedTreeHead(t, logs, priv, "witness-1")
+
+	tampered := append([]AuditLog{}, logs...)
+	tampered[2].Action = "delete_key"
+
+	c := &Client{}
+	err = c.VerifyAuditLog(context.Background(), tampered, sth, map[string]ed25519.PublicKey{"witness-1": pub})
+	if err == nil {
+		t.Fatal("expected error for a rewritten log entry (root mismatch)")
+	}
+}
+
+func TestVerifyAuditLogRejectsUnknownSigner(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	logs := sampleAuditLogs(3)
+	sth := buildSignedTreeHead(t, logs, priv, "witness-1")
+
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	c := &Client{}
+	err = c.VerifyAuditLog(context.Background(), logs, sth, map[string]ed25519.PublicKey{"witness-2": otherPub})
+	if err == nil {
+		t.Fatal("expected error: sth was signed by witness-1, not witness-2")
+	}
+}
+
+func TestVerifyAuditLogRequiresEveryCosignature(t *testing.T) {
+	pub1, priv1, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	_, priv2, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	logs := sampleAuditLogs(3)
+	sth := buildSignedTreeHead(t, logs, priv1, "service")
+	signTreeHead(priv2, "external-witness", sth)
+
+	c := &Client{}
+	// trustedKeys is missing "external-witness": the cosignature can't
+	// be checked, so verification must fail even though the service's
+	// own signature is valid.
+	err = c.VerifyAuditLog(context.Background(), logs, sth, map[string]ed25519.PublicKey{"service": pub1})
+	if err == nil {
+		t.Fatal("expected error: external-witness cosignature has no matching trusted key")
+	}
+}
+
+func TestVerifyInclusionProofRoundTrip(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	logs := sampleAuditLogs(7)
+	sth := buildSignedTreeHead(t, logs, priv, "witness-1")
+
+	leaves := make([][]byte, len(logs))
+	for i, log := range logs {
+		leaves[i], _ = auditLeafHash(log)
+	}
+
+	for leafIndex := range logs {
+		proof := buildTestInclusionProof(t, leaves, int64(leafIndex))
+		proof.TreeSize = sth.TreeSize
+
+		c := &Client{}
+		err := c.VerifyInclusionProof(logs[leafIndex], proof, sth, map[string]ed25519.PublicKey{"witness-1": pub})
+		if err != nil {
+			t.Errorf("VerifyInclusionProof(leaf %d): %v", leafIndex, err)
+		}
+	}
+}
+
+func TestVerifyInclusionProofRejectsWrongEntry(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	logs := sampleAuditLogs(7)
+	sth := buildSignedTreeHead(t, logs, priv, "witness-1")
+
+	leaves := make([][]byte, len(logs))
+	for i, log := range logs {
+		leaves[i], _ = auditLeafHash(log)
+	}
+	proof := buildTestInclusionProof(t, leaves, 3)
+	proof.TreeSize = sth.TreeSize
+
+	c := &Client{}
+	err = c.VerifyInclusionProof(logs[4], proof, sth, map[string]ed25519.PublicKey{"witness-1": pub})
+	if err == nil {
+		t.Fatal("expected error: proof is for leaf 3, not leaf 4's entry")
+	}
+}
+
+// buildTestInclusionProof computes a brute-force RFC 6962 audit path for
+// leaves[leafIndex] by recomputing subtree roots at each level, as a
+// reference implementation independent of the client's own verification
+// code, to drive round-trip tests of VerifyInclusionProof. Hashes are
+// collected on the way back up the recursion, so the nearest sibling
+// (bottom of the tree) comes first, matching what verifyMerkleInclusion
+// expects.
+func buildTestInclusionProof(t *testing.T, leaves [][]byte, leafIndex int64) *InclusionProof {
+	t.Helper()
+	return &InclusionProof{LeafIndex: leafIndex, Hashes: auditPathHashes(leaves, int(leafIndex))}
+}
+
+func auditPathHashes(nodes [][]byte, idx int) [][]byte {
+	if len(nodes) <= 1 {
+		return nil
+	}
+	k := largestPowerOfTwoLessThan(len(nodes))
+	if idx < k {
+		return append(auditPathHashes(nodes[:k], idx), merkleRoot(nodes[k:]))
+	}
+	return append(auditPathHashes(nodes[k:], idx-k), merkleRoot(nodes[:k]))
+}
+
+func TestAuditTreeHeadFetchesSignedTreeHead(t *testing.T) {
+	want := SignedTreeHead{
+		TreeSize:  3,
+		RootHash:  []byte{1, 2, 3, 4},
+		Timestamp: 1700000000,
+		Signatures: []Signature{
+			{KeyID: "witness-1", Sig: []byte{5, 6, 7, 8}},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/audit/tree-head" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(want)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithAdminToken("test"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	got, err := c.AuditTreeHead(context.Background())
+	if err != nil {
+		t.Fatalf("AuditTreeHead: %v", err)
+	}
+	if got.TreeSize != want.TreeSize || !bytes.Equal(got.RootHash, want.RootHash) {
+		t.Errorf("AuditTreeHead = %+v, want %+v", got, want)
+	}
+}
+
+func TestAuditInclusionProofSendsLogIDAndParsesResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("logId"); got != "log-42" {
+			t.Errorf("logId query param = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(InclusionProof{LeafIndex: 2, TreeSize: 5, Hashes: [][]byte{{9, 9}}})
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithAdminToken("test"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	proof, err := c.AuditInclusionProof(context.Background(), "log-42")
+	if err != nil {
+		t.Fatalf("AuditInclusionProof: %v", err)
+	}
+	if proof.LeafIndex != 2 || proof.TreeSize != 5 {
+		t.Errorf("proof = %+v", proof)
+	}
+}
+
+func TestAuditInclusionProofRejectsEmptyLogID(t *testing.T) {
+	c, err := New("http://example.invalid", WithAdminToken("test"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := c.AuditInclusionProof(context.Background(), ""); err == nil {
+		t.Fatal("expected error for empty logID")
+	}
+}
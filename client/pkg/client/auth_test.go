@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// recordingAuthenticator starts stale, becoming valid only after Refresh
+// is called, so tests can assert the transport refreshes exactly once
+// per 401 rather than looping.
+type recordingAuthenticator struct {
+	header    string
+	refreshes int
+}
+
+func (a *recordingAuthenticator) Apply(_ context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", a.header)
+	return nil
+}
+
+func (a *recordingAuthenticator) Refresh(context.Context) error {
+	a.refreshes++
+	a.header = "Bearer refreshed-token"
+	return nil
+}
+
+func TestStaticBearerAuthenticatorSetsAuthorizationHeader(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithAuthenticator(StaticBearerAuthenticator("initial-token")))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	resp, err := c.httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotAuth != "Bearer initial-token" {
+		t.Errorf("Authorization = %q, want %q", gotAuth, "Bearer initial-token")
+	}
+}
+
+func TestWithAuthProviderIsEquivalentToWithAuthenticator(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithAuthProvider(StaticBearerAuthenticator("provider-token")))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	resp, err := c.httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotAuth != "Bearer provider-token" {
+		t.Errorf("Authorization = %q, want %q", gotAuth, "Bearer provider-token")
+	}
+}
+
+func TestAuthenticatorTransportRefreshesOnceOn401(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		requests = append(requests, auth)
+		if auth != "Bearer refreshed-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	auth := &recordingAuthenticator{header: "Bearer stale-token"}
+	c, err := New(server.URL, WithAuthenticator(auth))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	resp, err := c.httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200 after refresh-and-retry", resp.StatusCode)
+	}
+	if auth.refreshes != 1 {
+		t.Errorf("refreshes = %d, want 1", auth.refreshes)
+	}
+	want := []string{"Bearer stale-token", "Bearer refreshed-token"}
+	if len(requests) != len(want) || requests[0] != want[0] || requests[1] != want[1] {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
+
+func TestAuthenticatorTransportDoesNotRetryTwiceOnPersistent401(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	auth := &recordingAuthenticator{header: "Bearer stale-token"}
+	c, err := New(server.URL, WithAuthenticator(auth))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	resp, err := c.httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", resp.StatusCode)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2 (original + single retry)", attempts)
+	}
+	if auth.refreshes != 1 {
+		t.Errorf("refreshes = %d, want 1", auth.refreshes)
+	}
+}
+
+func TestTokenSourceAuthenticatorForcesFetchOnRefresh(t *testing.T) {
+	var calls int
+	source := TokenSourceFunc(func(_ context.Context) (string, time.Duration, error) {
+		calls++
+		return fmt.Sprintf("token-%d", calls), time.Hour, nil
+	})
+
+	auth := TokenSourceAuthenticator(source)
+	ctx := context.Background()
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.invalid", nil)
+	if err := auth.Apply(ctx, req); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer token-1" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer token-1")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1 (Apply should fetch once and cache)", calls)
+	}
+
+	if err := auth.Apply(ctx, req); err != nil {
+		t.Fatalf("second Apply: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1 (second Apply should use the cached token)", calls)
+	}
+
+	if err := auth.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 (Refresh should force a fresh fetch)", calls)
+	}
+
+	if err := auth.Apply(ctx, req); err != nil {
+		t.Fatalf("third Apply: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer token-2" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer token-2")
+	}
+}
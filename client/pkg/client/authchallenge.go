@@ -0,0 +1,309 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// AuthChallenge is one WWW-Authenticate challenge (RFC 7235 section
+// 4.1): an auth-scheme (e.g. "Bearer") and its auth-param list.
+type AuthChallenge struct {
+	Scheme     string
+	Parameters map[string]string
+}
+
+// Realm, Service, and Scope read the challenge's conventional bearer-auth
+// parameters (as used by the OCI distribution token-auth spec), empty if
+// the challenge didn't carry one.
+func (c AuthChallenge) Realm() string   { return c.Parameters["realm"] }
+func (c AuthChallenge) Service() string { return c.Parameters["service"] }
+func (c AuthChallenge) Scope() string   { return c.Parameters["scope"] }
+
+// ParseAuthChallenges parses the WWW-Authenticate header value(s) of a
+// 401 response into one AuthChallenge per auth-scheme present. headers
+// is typically resp.Header.Values("WWW-Authenticate"); each entry may
+// itself list more than one challenge, separated by commas, per RFC
+// 7235's "WWW-Authenticate = 1#challenge". Parameter values follow RFC
+// 2617: either a bare token or a quoted-string with backslash escapes.
+func ParseAuthChallenges(headers []string) ([]AuthChallenge, error) {
+	var challenges []AuthChallenge
+	for _, h := range headers {
+		parsed, err := parseChallengeHeader(h)
+		if err != nil {
+			return nil, err
+		}
+		challenges = append(challenges, parsed...)
+	}
+	return challenges, nil
+}
+
+func parseChallengeHeader(header string) ([]AuthChallenge, error) {
+	p := &challengeScanner{s: header}
+	var challenges []AuthChallenge
+
+	for {
+		p.skipSpacesAndCommas()
+		if p.atEnd() {
+			break
+		}
+
+		scheme, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		challenge := AuthChallenge{Scheme: scheme, Parameters: map[string]string{}}
+
+		for {
+			save := p.i
+			p.skipSpaces()
+			if p.atEnd() || p.peek() == ',' {
+				p.i = save
+				break
+			}
+
+			name, err := p.token()
+			if err != nil {
+				p.i = save
+				break
+			}
+			p.skipSpaces()
+			if p.atEnd() || p.peek() != '=' {
+				// "name" wasn't followed by "=value": it's the next
+				// challenge's scheme, not a parameter of this one.
+				p.i = save
+				break
+			}
+			p.i++ // consume '='
+			p.skipSpaces()
+
+			var value string
+			if !p.atEnd() && p.peek() == '"' {
+				value, err = p.quotedString()
+			} else {
+				value, err = p.token()
+			}
+			if err != nil {
+				return nil, err
+			}
+			challenge.Parameters[strings.ToLower(name)] = value
+
+			p.skipSpaces()
+			if !p.atEnd() && p.peek() == ',' {
+				p.i++
+				continue
+			}
+			break
+		}
+
+		challenges = append(challenges, challenge)
+	}
+
+	return challenges, nil
+}
+
+// challengeScanner is a minimal backtracking scanner over RFC 7230
+// token / quoted-string grammar, just enough to parse auth challenges.
+type challengeScanner struct {
+	s string
+	i int
+}
+
+func (p *challengeScanner) atEnd() bool { return p.i >= len(p.s) }
+func (p *challengeScanner) peek() byte  { return p.s[p.i] }
+
+func (p *challengeScanner) skipSpaces() {
+	for !p.atEnd() && (p.s[p.i] == ' ' || p.s[p.i] == '\t') {
+		p.i++
+	}
+}
+
+func (p *challengeScanner) skipSpacesAndCommas() {
+	for !p.atEnd() && (p.s[p.i] == ' ' || p.s[p.i] == '\t' || p.s[p.i] == ',') {
+		p.i++
+	}
+}
+
+func (p *challengeScanner) token() (string, error) {
+	start := p.i
+	for !p.atEnd() && isChallengeTokenChar(p.s[p.i]) {
+		p.i++
+	}
+	if p.i == start {
+		return "", fmt.Errorf("client: parse auth challenge: expected token at offset %d in %q", start, p.s)
+	}
+	return p.s[start:p.i], nil
+}
+
+func (p *challengeScanner) quotedString() (string, error) {
+	if p.atEnd() || p.s[p.i] != '"' {
+		return "", fmt.Errorf("client: parse auth challenge: expected quoted-string at offset %d in %q", p.i, p.s)
+	}
+	p.i++
+
+	var b strings.Builder
+	for {
+		if p.atEnd() {
+			return "", fmt.Errorf("client: parse auth challenge: unterminated quoted-string in %q", p.s)
+		}
+		c := p.s[p.i]
+		switch {
+		case c == '"':
+			p.i++
+			return b.String(), nil
+		case c == '\\' && p.i+1 < len(p.s):
+			p.i++
+			b.WriteByte(p.s[p.i])
+			p.i++
+		default:
+			b.WriteByte(c)
+			p.i++
+		}
+	}
+}
+
+func isChallengeTokenChar(b byte) bool {
+	switch b {
+	case '(', ')', '<', '>', '@', ',', ';', ':', '\\', '"', '/', '[', ']', '?', '=', '{', '}', ' ', '\t':
+		return false
+	}
+	return b > 0x20 && b < 0x7f
+}
+
+// challengeTransport wraps an http.RoundTripper so a 401 response
+// carrying a Bearer WWW-Authenticate challenge (as used by OAuth2/OIDC-
+// fronted deployments following the OCI distribution token-auth spec)
+// triggers a token-exchange GET against the challenge's realm, caching
+// the resulting bearer token and retrying the original request with it.
+// A 401 without a Bearer challenge - an unrecognized scheme, or none at
+// all - is returned unchanged, so the client falls back to surfacing the
+// usual AuthError with whatever static credential was already applied.
+type challengeTransport struct {
+	next       http.RoundTripper
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	token string
+}
+
+func (t *challengeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	req = req.Clone(ctx)
+
+	t.mu.Lock()
+	token := t.token
+	t.mu.Unlock()
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// A request whose body can't be replayed is left as-is, same as
+	// authenticatorTransport: retrying it would send a truncated or
+	// empty body rather than the original one.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	challenge, ok := bearerChallenge(resp.Header.Values("WWW-Authenticate"))
+	if !ok {
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+
+	newToken, err := t.exchangeToken(ctx, challenge)
+	if err != nil {
+		return resp, nil
+	}
+
+	t.mu.Lock()
+	t.token = newToken
+	t.mu.Unlock()
+
+	retryReq := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retryReq.Body = body
+	}
+	retryReq.Header.Set("Authorization", "Bearer "+newToken)
+	return t.next.RoundTrip(retryReq)
+}
+
+// bearerChallenge picks the first Bearer challenge with a realm out of
+// headers, falling back to reporting none found for any other scheme
+// (including a malformed header that fails to parse).
+func bearerChallenge(headers []string) (AuthChallenge, bool) {
+	challenges, err := ParseAuthChallenges(headers)
+	if err != nil {
+		return AuthChallenge{}, false
+	}
+	for _, c := range challenges {
+		if strings.EqualFold(c.Scheme, "Bearer") && c.Realm() != "" {
+			return c, true
+		}
+	}
+	return AuthChallenge{}, false
+}
+
+func (t *challengeTransport) exchangeToken(ctx context.Context, challenge AuthChallenge) (string, error) {
+	u, err := url.Parse(challenge.Realm())
+	if err != nil {
+		return "", fmt.Errorf("client: auth challenge: parse realm: %w", err)
+	}
+
+	q := u.Query()
+	if service := challenge.Service(); service != "" {
+		q.Set("service", service)
+	}
+	if scope := challenge.Scope(); scope != "" {
+		q.Set("scope", scope)
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := doJSON(t.httpClient, req, &body); err != nil {
+		return "", fmt.Errorf("client: auth challenge: token exchange: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", errors.New("client: auth challenge: token exchange response had no token")
+}
+
+// WithAuthChallenge enables WWW-Authenticate Bearer challenge handling:
+// on a 401 response carrying a Bearer challenge, the client performs a
+// token-exchange GET against the challenge's realm (with the challenge's
+// service and scope as query parameters), caches the resulting token,
+// and retries the original request as a bearer token. Combine it with
+// WithTokenSource, WithOIDCToken, or WithAdminToken for deployments that
+// present a static or renewing credential up front but delegate to a
+// token-exchange realm for scoped operations; a 401 whose challenge
+// doesn't use the Bearer scheme (or has none at all) falls through
+// unchanged to whatever static credential was already applied.
+func WithAuthChallenge() Option {
+	return func(o *Options) {
+		o.authChallenge = true
+	}
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseAuthChallengesSingleChallengeQuotedParams(t *testing.T) {
+	challenges, err := ParseAuthChallenges([]string{
+		`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:foo:pull"`,
+	})
+	if err != nil {
+		t.Fatalf("ParseAuthChallenges: %v", err)
+	}
+	if len(challenges) != 1 {
+		t.Fatalf("expected 1 challenge, got %d", len(challenges))
+	}
+
+	c := challenges[0]
+	if c.Scheme != "Bearer" {
+		t.Errorf("Scheme = %q, want %q", c.Scheme, "Bearer")
+	}
+	if c.Realm() != "https://auth.example.com/token" {
+		t.Errorf("Realm() = %q", c.Realm())
+	}
+	if c.Service() != "registry.example.com" {
+		t.Errorf("Service() = %q", c.Service())
+	}
+	if c.Scope() != "repository:foo:pull" {
+		t.Errorf("Scope() = %q", c.Scope())
+	}
+}
+
+func TestParseAuthChallengesMultipleChallengesInOneHeader(t *testing.T) {
+	challenges, err := ParseAuthChallenges([]string{
+		`Bearer realm="https://auth.example.com/token",service="svc", Basic realm="legacy"`,
+	})
+	if err != nil {
+		t.Fatalf("ParseAuthChallenges: %v", err)
+	}
+	if len(challenges) != 2 {
+		t.Fatalf("expected 2 challenges, got %d: %+v", len(challenges), challenges)
+	}
+	if challenges[0].Scheme != "Bearer" || challenges[0].Service() != "svc" {
+		t.Errorf("challenges[0] = %+v", challenges[0])
+	}
+	if challenges[1].Scheme != "Basic" || challenges[1].Realm() != "legacy" {
+		t.Errorf("challenges[1] = %+v", challenges[1])
+	}
+}
+
+func TestParseAuthChallengesMultipleHeaderValues(t *testing.T) {
+	challenges, err := ParseAuthChallenges([]string{
+		`Bearer realm="https://a.example.com/token"`,
+		`Basic realm="b"`,
+	})
+	if err != nil {
+		t.Fatalf("ParseAuthChallenges: %v", err)
+	}
+	if len(challenges) != 2 {
+		t.Fatalf("expected 2 challenges, got %d", len(challenges))
+	}
+}
+
+func TestParseAuthChallengesEscapedQuotedString(t *testing.T) {
+	challenges, err := ParseAuthChallenges([]string{`Bearer realm="quote: \" inside"`})
+	if err != nil {
+		t.Fatalf("ParseAuthChallenges: %v", err)
+	}
+	if got := challenges[0].Realm(); got != `quote: " inside` {
+		t.Errorf("Realm() = %q", got)
+	}
+}
+
+func TestParseAuthChallengesUnknownSchemeHasNoRealm(t *testing.T) {
+	challenges, err := ParseAuthChallenges([]string{`Negotiate`})
+	if err != nil {
+		t.Fatalf("ParseAuthChallenges: %v", err)
+	}
+	if len(challenges) != 1 || challenges[0].Scheme != "Negotiate" {
+		t.Fatalf("expected a single Negotiate challenge, got %+v", challenges)
+	}
+	if challenges[0].Realm() != "" {
+		t.Errorf("expected no realm, got %q", challenges[0].Realm())
+	}
+}
+
+func TestBearerChallengeFallsBackWhenSchemeIsUnrecognized(t *testing.T) {
+	if _, ok := bearerChallenge([]string{`Basic realm="legacy"`}); ok {
+		t.Error("expected no Bearer challenge to be found")
+	}
+	if _, ok := bearerChallenge(nil); ok {
+		t.Error("expected no Bearer challenge with no WWW-Authenticate header")
+	}
+	if _, ok := bearerChallenge([]string{`not a valid challenge ===`}); ok {
+		t.Error("expected a malformed header to fall back to no challenge found")
+	}
+}
+
+func TestWithAuthChallengeExchangesTokenAndRetries(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("service"); got != "gpg-signing-service" {
+			t.Errorf("service query param = %q", got)
+		}
+		if got := r.URL.Query().Get("scope"); got != "sign:push" {
+			t.Errorf("scope query param = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"token":"exchanged-token"}`))
+	}))
+	defer tokenServer.Close()
+
+	var requests []string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		requests = append(requests, auth)
+		if auth != "Bearer exchanged-token" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+tokenServer.URL+`",service="gpg-signing-service",scope="sign:push"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer apiServer.Close()
+
+	c, err := New(apiServer.URL, WithAuthChallenge())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	resp, err := c.httpClient.Get(apiServer.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200 after challenge exchange and retry", resp.StatusCode)
+	}
+	if len(requests) != 2 || requests[1] != "Bearer exchanged-token" {
+		t.Errorf("requests = %v", requests)
+	}
+}
+
+func TestWithAuthChallengeFallsThroughOnNonBearerChallenge(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		w.Header().Set("WWW-Authenticate", `Basic realm="legacy"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithAuthChallenge())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	resp, err := c.httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401 (no Bearer challenge to act on)", resp.StatusCode)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1 (no retry without a Bearer challenge)", attempts)
+	}
+}
+
+func TestWithAuthChallengeCachesTokenAcrossRequests(t *testing.T) {
+	var tokenRequests int
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		tokenRequests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"token":"cached-token"}`))
+	}))
+	defer tokenServer.Close()
+
+	var apiRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiRequests++
+		if r.Header.Get("Authorization") != "Bearer cached-token" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+tokenServer.URL+`"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithAuthChallenge())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.httpClient.Get(server.URL)
+		if err != nil {
+			t.Fatalf("GET %d: %v", i, err)
+		}
+		resp.Body.Close()
+	}
+
+	if tokenRequests != 1 {
+		t.Errorf("tokenRequests = %d, want 1 (cached token reused on 2nd call)", tokenRequests)
+	}
+	if apiRequests != 3 {
+		t.Errorf("apiRequests = %d, want 3 (401+retry on 1st call, single request on 2nd)", apiRequests)
+	}
+}
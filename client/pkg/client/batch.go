@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kjanat/gpg-signing-service/client/pkg/api"
+)
+
+// defaultMaxBatchSize caps the number of items SignBatch sends in a
+// single HTTP request unless overridden with WithMaxBatchSize.
+const defaultMaxBatchSize = 100
+
+// SignItem is a single payload submitted to SignBatch.
+type SignItem struct {
+	CommitData string
+	KeyID      string // empty uses the default key
+}
+
+// SignItemResult is the outcome of one SignItem within a BatchSignResult.
+// Exactly one of Signature or Err is set.
+type SignItemResult struct {
+	Signature string
+	Err       error
+}
+
+// BatchSignResult is the outcome of SignBatch. RequestID correlates all
+// items in the audit log, since the server processes a batch as a
+// single transaction. When SignBatch splits items over several requests,
+// RequestID is that of the first request.
+type BatchSignResult struct {
+	RequestID string
+	Results   []SignItemResult
+	RateLimit *BatchRateLimit
+}
+
+// BatchRateLimit reports the rate-limit headroom consumed by a batch
+// request, which counts against the quota once per batch rather than
+// once per item.
+type BatchRateLimit struct {
+	Remaining int
+	Reset     time.Time
+}
+
+// SignBatch submits multiple commit payloads in a single HTTP request
+// and returns one result per input item, preserving order. Items beyond
+// the client's configured max batch size are split into additional
+// sequential requests sharing the same keyID.
+func (c *Client) SignBatch(ctx context.Context, items []SignItem, keyID string) (*BatchSignResult, error) {
+	if len(items) == 0 {
+		return nil, &ValidationError{Code: "INVALID_REQUEST", Message: "items cannot be empty"}
+	}
+
+	maxBatch := c.opts.maxBatchSize
+	if maxBatch <= 0 {
+		maxBatch = defaultMaxBatchSize
+	}
+
+	combined := &BatchSignResult{}
+	for start := 0; start < len(items); start += maxBatch {
+		end := start + maxBatch
+		if end > len(items) {
+			end = len(items)
+		}
+
+		chunk, err := c.signBatchChunk(ctx, items[start:end], keyID)
+		if err != nil {
+			return nil, err
+		}
+		if combined.RequestID == "" {
+			combined.RequestID = chunk.RequestID
+		}
+		combined.Results = append(combined.Results, chunk.Results...)
+		combined.RateLimit = chunk.RateLimit
+	}
+
+	return combined, nil
+}
+
+func (c *Client) signBatchChunk(ctx context.Context, items []SignItem, keyID string) (*BatchSignResult, error) {
+	body := api.PostSignBatchJSONRequestBody{
+		KeyId: keyIDPtrOrNil(keyID),
+		Items: make([]api.SignBatchItem, len(items)),
+	}
+	for i, item := range items {
+		body.Items[i] = api.SignBatchItem{
+			CommitData: item.CommitData,
+			KeyId:      keyIDPtrOrNil(item.KeyID),
+		}
+	}
+
+	var resp *api.PostSignBatchResponse
+	err := c.retrier.Do(ctx, func() error {
+		var execErr error
+		resp, execErr = c.raw.PostSignBatchWithResponse(ctx, body)
+		return execErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.JSON200 != nil {
+		results := make([]SignItemResult, len(resp.JSON200.Results))
+		for i, r := range resp.JSON200.Results {
+			results[i] = mapBatchItemResult(r)
+		}
+		requestID := ""
+		if resp.JSON200.RequestId != nil {
+			requestID = resp.JSON200.RequestId.String()
+		}
+		return &BatchSignResult{
+			RequestID: requestID,
+			Results:   results,
+			RateLimit: parseBatchRateLimitHeaders(resp.HTTPResponse),
+		}, nil
+	}
+	if resp.JSON400 != nil {
+		return nil, &ValidationError{Code: string(resp.JSON400.Code), Message: resp.JSON400.Error}
+	}
+	if resp.JSON429 != nil {
+		return nil, &RateLimitError{
+			Message:    resp.JSON429.Error,
+			RetryAfter: time.Duration(resp.JSON429.RetryAfter) * time.Second,
+		}
+	}
+	if resp.JSON500 != nil {
+		return nil, newServiceError(resp.HTTPResponse, string(resp.JSON500.Code), resp.JSON500.Error, 500)
+	}
+	return nil, newUnexpectedStatusError(resp.StatusCode())
+}
+
+func mapBatchItemResult(r api.SignBatchItemResult) SignItemResult {
+	if r.Error != nil {
+		return SignItemResult{Err: &ValidationError{Code: string(r.Error.Code), Message: r.Error.Error}}
+	}
+	if r.Signature != nil {
+		return SignItemResult{Signature: *r.Signature}
+	}
+	return SignItemResult{}
+}
+
+func parseBatchRateLimitHeaders(resp *http.Response) *BatchRateLimit {
+	if resp == nil {
+		return nil
+	}
+
+	rl := &BatchRateLimit{}
+	if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
+		if val, err := strconv.Atoi(remaining); err == nil {
+			rl.Remaining = val
+		}
+	}
+	if reset := resp.Header.Get("X-RateLimit-Reset"); reset != "" {
+		if val, err := strconv.ParseInt(reset, 10, 64); err == nil {
+			rl.Reset = time.Unix(val, 0)
+		}
+	}
+	return rl
+}
+
+func keyIDPtrOrNil(keyID string) *string {
+	if keyID == "" {
+		return nil
+	}
+	return &keyID
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSignBatchRejectsEmptyItems(t *testing.T) {
+	c := &Client{opts: defaultOptions()}
+	_, err := c.SignBatch(nil, nil, "")
+	if !IsValidationError(err) {
+		t.Errorf("expected ValidationError, got %v", err)
+	}
+}
+
+// TestSignBatchMapsRateLimitRetryAfter verifies that a 429 response to
+// SignBatch populates RateLimitError.RetryAfter from the body, the same
+// way Sign's 429 mapping does, rather than leaving it zero and falling
+// back to generic backoff.
+func TestSignBatchMapsRateLimitRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": "RATE_LIMITED", "error": "slow down", "retryAfter": 4})
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	_, err = c.SignBatch(context.Background(), []SignItem{{CommitData: "data"}}, "")
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("expected a *RateLimitError, got %v", err)
+	}
+	if rateLimitErr.RetryAfter != 4*time.Second {
+		t.Errorf("RetryAfter = %v, want 4s", rateLimitErr.RetryAfter)
+	}
+}
+
+func TestKeyIDPtrOrNil(t *testing.T) {
+	if keyIDPtrOrNil("") != nil {
+		t.Error("expected nil pointer for empty keyID")
+	}
+	if p := keyIDPtrOrNil("abc"); p == nil || *p != "abc" {
+		t.Errorf("expected pointer to 'abc', got %v", p)
+	}
+}
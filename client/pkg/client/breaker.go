@@ -0,0 +1,230 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// BreakerState is the circuit breaker's current state.
+type BreakerState int
+
+// Circuit breaker states, following the standard closed/open/half-open
+// state machine (as used by Sony's gobreaker).
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrCircuitOpen is returned when a request is rejected because the
+// circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerConfig configures a circuit breaker.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive qualifying failures
+	// (5xx ServiceErrors and network errors) that trips the breaker. The
+	// count has no time window: only a counted success resets it.
+	FailureThreshold int
+	// SuccessThreshold is the number of consecutive successful half-open
+	// probes required to close the breaker again. Defaults to 1.
+	SuccessThreshold int
+	// OpenDuration is how long the breaker stays open before admitting
+	// half-open probes (up to HalfOpenMaxProbes).
+	OpenDuration time.Duration
+	// HalfOpenMaxProbes is the number of concurrent probes allowed while
+	// half-open. Typically 1.
+	HalfOpenMaxProbes int
+}
+
+func defaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		FailureThreshold:  5,
+		SuccessThreshold:  1,
+		OpenDuration:      30 * time.Second,
+		HalfOpenMaxProbes: 1,
+	}
+}
+
+// circuitBreaker wraps the Retrier with a fail-fast state machine keyed
+// on SERVICE_DEGRADED / 5xx failures. ValidationError and RateLimitError
+// never count toward tripping.
+type circuitBreaker struct {
+	cfg BreakerConfig
+
+	mu            sync.Mutex
+	state         BreakerState
+	failures      int
+	successes     int
+	openedAt      time.Time
+	halfOpenProbe int
+}
+
+func newCircuitBreaker(cfg BreakerConfig) *circuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaultBreakerConfig().FailureThreshold
+	}
+	if cfg.SuccessThreshold <= 0 {
+		cfg.SuccessThreshold = defaultBreakerConfig().SuccessThreshold
+	}
+	if cfg.OpenDuration <= 0 {
+		cfg.OpenDuration = defaultBreakerConfig().OpenDuration
+	}
+	if cfg.HalfOpenMaxProbes <= 0 {
+		cfg.HalfOpenMaxProbes = defaultBreakerConfig().HalfOpenMaxProbes
+	}
+	return &circuitBreaker{cfg: cfg, state: BreakerClosed}
+}
+
+// allow reports whether a request may proceed, transitioning open->half-open
+// once OpenDuration has elapsed.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerClosed:
+		return nil
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cfg.OpenDuration {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.halfOpenProbe = 0
+		fallthrough
+	case BreakerHalfOpen:
+		if b.halfOpenProbe >= b.cfg.HalfOpenMaxProbes {
+			return ErrCircuitOpen
+		}
+		b.halfOpenProbe++
+		return nil
+	}
+	return nil
+}
+
+func (b *circuitBreaker) recordResult(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !countsTowardBreaker(err) {
+		return
+	}
+
+	if err == nil {
+		if b.state == BreakerHalfOpen {
+			b.successes++
+			if b.successes >= b.cfg.SuccessThreshold {
+				b.reset()
+			} else {
+				// Allow another sequential probe while still collecting
+				// the remaining successes needed to close.
+				b.halfOpenProbe = 0
+			}
+			return
+		}
+		b.failures = 0
+		return
+	}
+
+	if b.state == BreakerHalfOpen {
+		b.trip()
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.cfg.FailureThreshold {
+		b.trip()
+	}
+}
+
+func (b *circuitBreaker) trip() {
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+	b.failures = 0
+	b.successes = 0
+}
+
+func (b *circuitBreaker) reset() {
+	b.state = BreakerClosed
+	b.failures = 0
+	b.successes = 0
+	b.halfOpenProbe = 0
+}
+
+func (b *circuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// countsTowardBreaker reports whether err should influence the breaker's
+// state. Successes, 5xx ServiceErrors, and network errors (anything not
+// recognized as one of this package's typed API errors) count; 4xx
+// ServiceErrors, RateLimitError, ValidationError, AuthError, and context
+// cancellation do not, since those indicate a problem with the request or
+// caller rather than the backend's health.
+func countsTowardBreaker(err error) bool {
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var rl *RateLimitError
+	var ve *ValidationError
+	var ae *AuthError
+	if errors.As(err, &rl) || errors.As(err, &ve) || errors.As(err, &ae) {
+		return false
+	}
+
+	var se *ServiceError
+	if errors.As(err, &se) {
+		return se.StatusCode >= 500
+	}
+
+	// An error that isn't one of this package's typed API errors means no
+	// (usable) response was ever received, e.g. connection refused, DNS
+	// failure, or a timed-out dial - a network error, which counts.
+	return true
+}
+
+// WithCircuitBreaker enables a client-side circuit breaker that fails
+// fast once the backend looks unhealthy, instead of retrying into a
+// known-bad service. Health is probed automatically during the open
+// state so recovery is detected without user traffic.
+func WithCircuitBreaker(cfg BreakerConfig) Option {
+	return func(o *Options) {
+		o.breaker = newCircuitBreaker(cfg)
+	}
+}
+
+// CircuitState returns the circuit breaker's current state, or
+// BreakerClosed if no breaker was configured via WithCircuitBreaker.
+func (c *Client) CircuitState() BreakerState {
+	if c.opts.breaker == nil {
+		return BreakerClosed
+	}
+	return c.opts.breaker.State()
+}
+
+// probeHealth issues a single health check while the breaker is open, so
+// that recovery is detected without relying on user-initiated traffic.
+func (c *Client) probeHealth(ctx context.Context) {
+	_, _ = c.Health(ctx)
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTripsAfterThreshold(t *testing.T) {
+	b := newCircuitBreaker(BreakerConfig{FailureThreshold: 3, OpenDuration: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		if err := b.allow(); err != nil {
+			t.Fatalf("allow() before threshold: %v", err)
+		}
+		b.recordResult(&ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500})
+	}
+	if b.State() != BreakerClosed {
+		t.Fatalf("expected closed before threshold, got %s", b.State())
+	}
+
+	b.recordResult(&ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500})
+	if b.State() != BreakerOpen {
+		t.Fatalf("expected open after threshold, got %s", b.State())
+	}
+
+	if err := b.allow(); err != ErrCircuitOpen {
+		t.Errorf("expected ErrCircuitOpen while open, got %v", err)
+	}
+}
+
+func TestCircuitBreakerHalfOpenRecovery(t *testing.T) {
+	b := newCircuitBreaker(BreakerConfig{FailureThreshold: 1, OpenDuration: time.Millisecond})
+	b.recordResult(&ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500})
+	if b.State() != BreakerOpen {
+		t.Fatalf("expected open, got %s", b.State())
+	}
+
+	time.Sleep(2 * time.Millisecond)
+
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected half-open probe to be allowed, got %v", err)
+	}
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("expected half-open, got %s", b.State())
+	}
+
+	b.recordResult(nil)
+	if b.State() != BreakerClosed {
+		t.Fatalf("expected closed after successful probe, got %s", b.State())
+	}
+}
+
+func TestCircuitBreakerIgnoresRateLimitAndValidationErrors(t *testing.T) {
+	b := newCircuitBreaker(BreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute})
+
+	b.recordResult(&RateLimitError{Message: "slow down"})
+	b.recordResult(&ValidationError{Code: "INVALID_REQUEST", Message: "bad input"})
+
+	if b.State() != BreakerClosed {
+		t.Errorf("expected closed, rate limit/validation errors should not trip the breaker, got %s", b.State())
+	}
+}
+
+func TestCircuitBreakerIgnores4xxAndContextCanceled(t *testing.T) {
+	b := newCircuitBreaker(BreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute})
+
+	b.recordResult(&ServiceError{Code: "NOT_FOUND", StatusCode: 404})
+	b.recordResult(context.Canceled)
+	b.recordResult(&AuthError{Message: "unauthorized"})
+
+	if b.State() != BreakerClosed {
+		t.Errorf("expected closed, 4xx/context-canceled/auth errors should not trip the breaker, got %s", b.State())
+	}
+}
+
+func TestCircuitBreakerCountsNetworkErrors(t *testing.T) {
+	b := newCircuitBreaker(BreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute})
+
+	b.recordResult(errors.New("connection refused"))
+
+	if b.State() != BreakerOpen {
+		t.Errorf("expected open, a raw network error should count as a failure, got %s", b.State())
+	}
+}
+
+func TestCircuitBreakerSuccessThresholdRequiresMultipleProbes(t *testing.T) {
+	b := newCircuitBreaker(BreakerConfig{FailureThreshold: 1, SuccessThreshold: 2, OpenDuration: time.Millisecond})
+	b.recordResult(&ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500})
+	if b.State() != BreakerOpen {
+		t.Fatalf("expected open, got %s", b.State())
+	}
+
+	time.Sleep(2 * time.Millisecond)
+
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected first half-open probe to be allowed, got %v", err)
+	}
+	b.recordResult(nil)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("expected to stay half-open after 1 of 2 required successes, got %s", b.State())
+	}
+
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected second half-open probe to be allowed, got %v", err)
+	}
+	b.recordResult(nil)
+	if b.State() != BreakerClosed {
+		t.Fatalf("expected closed after 2nd consecutive success, got %s", b.State())
+	}
+}
+
+func TestCircuitBreakerHalfOpenFailureReopens(t *testing.T) {
+	b := newCircuitBreaker(BreakerConfig{FailureThreshold: 1, SuccessThreshold: 2, OpenDuration: time.Millisecond})
+	b.recordResult(&ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500})
+	time.Sleep(2 * time.Millisecond)
+
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected probe to be allowed, got %v", err)
+	}
+	b.recordResult(&ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500})
+
+	if b.State() != BreakerOpen {
+		t.Fatalf("expected a half-open failure to reopen the breaker, got %s", b.State())
+	}
+}
+
+// TestClientCircuitStateFailsFastAndRecovers drives the breaker through a
+// Client's retrier using a server that flips between 500 and 200.
+func TestClientCircuitStateFailsFastAndRecovers(t *testing.T) {
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if healthy.Load() {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"status":"healthy","checks":{"keyStorage":true,"database":true}}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"code":"INTERNAL_ERROR","error":"boom"}`))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL,
+		WithMaxRetries(0),
+		WithCircuitBreaker(BreakerConfig{FailureThreshold: 2, OpenDuration: 10 * time.Millisecond, HalfOpenMaxProbes: 1}),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if c.CircuitState() != BreakerClosed {
+		t.Fatalf("expected closed initially, got %s", c.CircuitState())
+	}
+
+	for i := 0; i < 2; i++ {
+		_, _ = c.Health(context.Background())
+	}
+	if c.CircuitState() != BreakerOpen {
+		t.Fatalf("expected open after 2 consecutive 5xx, got %s", c.CircuitState())
+	}
+
+	if _, err := c.Health(context.Background()); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen while open, got %v", err)
+	}
+
+	healthy.Store(true)
+	time.Sleep(15 * time.Millisecond)
+
+	if _, err := c.Health(context.Background()); err != nil {
+		t.Fatalf("expected half-open probe to succeed: %v", err)
+	}
+	if c.CircuitState() != BreakerClosed {
+		t.Errorf("expected closed after successful probe, got %s", c.CircuitState())
+	}
+}
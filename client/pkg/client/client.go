@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kjanat/gpg-signing-service/client/pkg/api"
@@ -21,9 +22,24 @@ import (
 //
 // Do not copy a Client after first use.
 type Client struct {
-	raw     *api.ClientWithResponses
-	opts    *Options
-	retrier *Retrier
+	raw          *api.ClientWithResponses
+	opts         *Options
+	retrier      *Retrier
+	tokenRenewer *TokenRenewer
+	baseURL      string
+	httpClient   *http.Client
+	closeOnce    sync.Once
+	closed       chan struct{}
+	transport    *batchTransport
+
+	directoryMu sync.Mutex
+	directory   *serviceDirectory
+
+	reqSigner *requestSigner
+	nonceMu   sync.Mutex
+	nonce     string
+
+	jwks *jwksVerifier
 }
 
 // New creates a new GPG Signing Service client.
@@ -54,15 +70,85 @@ func New(baseURL string, opts ...Option) (*Client, error) {
 		return nil, errors.New("retryWaitMin must be less than retryWaitMax")
 	}
 
+	resolvedBaseURL, unixSocketPath, err := resolveUnixSocket(baseURL, options.unixSocketPath)
+	if err != nil {
+		return nil, err
+	}
+	if unixSocketPath != "" && !strings.HasPrefix(unixSocketPath, "/") {
+		return nil, errors.New("client: unix socket path must be absolute")
+	}
+	baseURL = resolvedBaseURL
+
 	httpClient := &http.Client{
 		Timeout: options.timeout,
 	}
 
+	transport := &http.Transport{}
+	usesCustomTransport := false
+
+	if unixSocketPath != "" {
+		transport.DialContext = unixDialContext(unixSocketPath)
+		usesCustomTransport = true
+	}
+
+	tlsConfig := options.tlsConfig
+	if options.tlsCertFile != "" || options.tlsKeyFile != "" || options.tlsCAFile != "" {
+		tlsConfig, err = buildMTLSConfig(options.tlsCertFile, options.tlsKeyFile, options.tlsCAFile, tlsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("client: configure mTLS: %w", err)
+		}
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+		usesCustomTransport = true
+	}
+
+	var rt http.RoundTripper
+	if usesCustomTransport {
+		rt = transport
+	}
+	if len(options.middleware) > 0 {
+		if rt == nil {
+			rt = http.DefaultTransport
+		}
+		for i := len(options.middleware) - 1; i >= 0; i-- {
+			rt = options.middleware[i](rt)
+		}
+		usesCustomTransport = true
+	}
+	if options.authenticator != nil {
+		if rt == nil {
+			rt = http.DefaultTransport
+		}
+		rt = &authenticatorTransport{next: rt, auth: options.authenticator}
+		usesCustomTransport = true
+	}
+	if options.authChallenge {
+		if rt == nil {
+			rt = http.DefaultTransport
+		}
+		rt = &challengeTransport{next: rt, httpClient: &http.Client{Timeout: options.timeout}}
+		usesCustomTransport = true
+	}
+	if usesCustomTransport {
+		httpClient.Transport = rt
+	}
+
 	clientOpts := []api.ClientOption{
 		api.WithHTTPClient(httpClient),
 	}
 
-	if options.authToken != "" {
+	switch {
+	case options.tokenRenewer != nil:
+		renewer := options.tokenRenewer
+		if err := renewer.Start(context.Background()); err != nil {
+			return nil, fmt.Errorf("start token renewer: %w", err)
+		}
+		clientOpts = append(clientOpts, api.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+			req.Header.Set("Authorization", renewer.Header())
+			return nil
+		}))
+	case options.authToken != "":
 		// Pre-allocate auth header to avoid allocation on every request
 		authHeader := "Bearer " + options.authToken
 		clientOpts = append(clientOpts, api.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
@@ -71,16 +157,112 @@ func New(baseURL string, opts ...Option) (*Client, error) {
 		}))
 	}
 
+	if options.idempotencyKeyFunc != nil {
+		clientOpts = append(clientOpts, api.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+			if req.Method != http.MethodPost {
+				return nil
+			}
+			if key := options.idempotencyKeyFunc(); key != "" {
+				req.Header.Set("Idempotency-Key", key)
+			}
+			return nil
+		}))
+	}
+
 	rawClient, err := api.NewClientWithResponses(baseURL, clientOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("create client: %w", err)
 	}
 
-	return &Client{
-		raw:     rawClient,
-		opts:    options,
-		retrier: newRetrier(options),
-	}, nil
+	var reqSigner *requestSigner
+	if options.requestSigningKey != nil {
+		reqSigner, err = newRequestSigner(options.requestSigningKey, options.requestSigningKID)
+		if err != nil {
+			return nil, fmt.Errorf("client: configure request signing key: %w", err)
+		}
+	}
+
+	var jwks *jwksVerifier
+	if options.jwksURL != nil {
+		jwksDocURL := *options.jwksURL
+		if jwksDocURL == "" {
+			jwksDocURL = strings.TrimRight(baseURL, "/") + defaultJWKSPath
+		}
+		jwks = newJWKSVerifier(jwksDocURL, options.jwksCacheTTL)
+	}
+
+	c := &Client{
+		raw:          rawClient,
+		opts:         options,
+		retrier:      newRetrier(options),
+		tokenRenewer: options.tokenRenewer,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		httpClient:   httpClient,
+		closed:       make(chan struct{}),
+		reqSigner:    reqSigner,
+		jwks:         jwks,
+	}
+
+	if options.breaker != nil {
+		go c.runBreakerProbes(options.breaker)
+	}
+
+	if options.useBatchTransport {
+		c.transport = newBatchTransport(c, options.batchSize, options.batchLinger)
+	}
+
+	return c, nil
+}
+
+// runBreakerProbes periodically hits Health while the breaker is open so
+// that recovery is detected without relying on user traffic to trip the
+// half-open probe.
+func (c *Client) runBreakerProbes(b *circuitBreaker) {
+	ticker := time.NewTicker(b.cfg.OpenDuration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.closed:
+			return
+		case <-ticker.C:
+			if b.State() == BreakerOpen {
+				c.probeHealth(context.Background())
+			}
+		}
+	}
+}
+
+// authHeader returns the current "Bearer <token>" value for manually
+// constructed requests (e.g. streaming endpoints not covered by the
+// generated API client).
+func (c *Client) authHeader() string {
+	if c.tokenRenewer != nil {
+		return c.tokenRenewer.Header()
+	}
+	if c.opts.authToken == "" {
+		return ""
+	}
+	return "Bearer " + c.opts.authToken
+}
+
+// TokenRenewer returns the background token renewer configured via
+// WithTokenRenewer, or nil if the client was constructed with a static
+// token. Callers that configure a renewer are responsible for calling
+// Stop on it (directly, or via Client.Close) when done with the client.
+func (c *Client) TokenRenewer() *TokenRenewer {
+	return c.tokenRenewer
+}
+
+// Close stops the client's background token renewer and circuit breaker
+// health prober, if configured. It is safe to call more than once.
+func (c *Client) Close() error {
+	if c.tokenRenewer != nil {
+		c.tokenRenewer.Stop()
+	}
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+	return nil
 }
 
 // Health checks service health.
@@ -107,16 +289,12 @@ func (c *Client) Health(ctx context.Context) (*HealthStatus, error) {
 
 	if resp.JSON503 != nil {
 		return &HealthStatus{
-				Status:     string(resp.JSON503.Status),
-				Version:    resp.JSON503.Version,
-				Timestamp:  resp.JSON503.Timestamp,
-				KeyStorage: resp.JSON503.Checks.KeyStorage,
-				Database:   resp.JSON503.Checks.Database,
-			}, &ServiceError{
-				Code:       ErrCodeDegraded,
-				Message:    "service degraded",
-				StatusCode: 503,
-			}
+			Status:     string(resp.JSON503.Status),
+			Version:    resp.JSON503.Version,
+			Timestamp:  resp.JSON503.Timestamp,
+			KeyStorage: resp.JSON503.Checks.KeyStorage,
+			Database:   resp.JSON503.Checks.Database,
+		}, newServiceError(resp.HTTPResponse, ErrCodeDegraded, "service degraded", 503)
 	}
 
 	return nil, newUnexpectedStatusError(resp.StatusCode())
@@ -143,7 +321,10 @@ func (c *Client) PublicKey(ctx context.Context, keyID string) (string, error) {
 	}
 
 	if resp.StatusCode() == 200 {
-		publicKey := string(resp.Body)
+		publicKey, err := c.verifyKeyResponse(ctx, resp.HTTPResponse, resp.Body)
+		if err != nil {
+			return "", err
+		}
 		if !strings.HasPrefix(publicKey, "-----BEGIN PGP PUBLIC KEY BLOCK-----") {
 			return "", fmt.Errorf("invalid PGP key format")
 		}
@@ -159,11 +340,7 @@ func (c *Client) PublicKey(ctx context.Context, keyID string) (string, error) {
 	}
 
 	if resp.JSON500 != nil {
-		return "", &ServiceError{
-			Code:       string(resp.JSON500.Code),
-			Message:    resp.JSON500.Error,
-			StatusCode: 500,
-		}
+		return "", newServiceError(resp.HTTPResponse, string(resp.JSON500.Code), resp.JSON500.Error, 500)
 	}
 
 	return "", newUnexpectedStatusError(resp.StatusCode())
@@ -171,17 +348,98 @@ func (c *Client) PublicKey(ctx context.Context, keyID string) (string, error) {
 
 // Sign signs commit data and returns the signature.
 // Pass an empty string for keyID to use the default key.
-func (c *Client) Sign(ctx context.Context, commitData string, keyID string) (*SignResult, error) {
+//
+// When the client was constructed with WithTransport, concurrent Sign
+// calls may be coalesced into a single POST /sign/batch request; the
+// caller-visible signature and error semantics are unchanged.
+//
+// Passing Async(true) makes Sign return as soon as the server accepts
+// the request: the returned SignResult carries only a JobID, which
+// WaitSignature resolves to the finished signature.
+//
+// Every synchronous call not routed through the batch transport carries
+// an Idempotency-Key: either the one set via WithIdempotencyKey, or an
+// automatically generated UUIDv4 otherwise. Async calls and calls routed
+// through the batch transport are sent without this per-call key.
+// The same key is resent on every retry attempt so the server can
+// deduplicate a request it already processed. If the client was
+// constructed with WithIdempotencyCache, a call whose key and
+// (commitData, keyID) match a still-fresh cache entry returns that
+// cached SignResult without a network round trip; only successful
+// results are cached. Request signing (reqSigner) and the batch
+// transport bypass this cache, since neither maps cleanly onto a single
+// cacheable request/response pair.
+func (c *Client) Sign(ctx context.Context, commitData string, keyID string, opts ...SignOption) (*SignResult, error) {
 	if err := validateSignInput(commitData); err != nil {
 		return nil, err
 	}
 
+	var o signCallOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.async {
+		return c.signAsync(ctx, commitData, keyID)
+	}
+
+	idemKey := o.idempotencyKey
+	if idemKey == "" {
+		var err error
+		idemKey, err = newIdempotencyKey()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.reqSigner != nil {
+		return c.signSigned(ctx, commitData, keyID, idemKey)
+	}
+
+	if c.transport != nil {
+		return c.transport.sign(ctx, commitData, keyID)
+	}
+
+	if cache := c.opts.idempotencyCache; cache != nil {
+		hash := requestHash(commitData, keyID)
+		if cached, ok := cache.get(idemKey, hash); ok {
+			return &cached, nil
+		}
+
+		result, err := c.signDirect(ctx, commitData, keyID, idemKey)
+		if err != nil {
+			return nil, err
+		}
+		cache.put(idemKey, hash, *result)
+		return result, nil
+	}
+
+	return c.signDirect(ctx, commitData, keyID, idemKey)
+}
+
+// signDirect issues a single POST /sign request, bypassing the batch
+// transport. It is also used by batchTransport to handle a lone pending
+// job once the linger deadline passes.
+func (c *Client) signDirect(ctx context.Context, commitData string, keyID string, idempotencyKey string) (*SignResult, error) {
 	params := buildSignParams(keyID)
+	setIdempotencyKey := func(_ context.Context, req *http.Request) error {
+		if idempotencyKey != "" {
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+		return nil
+	}
 
 	var resp *api.PostSignResponse
-	err := c.retrier.Do(ctx, func() error {
+	err := c.retrier.DoNonIdempotent(ctx, func() error {
 		var execErr error
-		resp, execErr = c.raw.PostSignWithBodyWithResponse(ctx, params, "text/plain", strings.NewReader(commitData))
+		resp, execErr = c.raw.PostSignWithBodyWithResponse(ctx, params, "text/plain", strings.NewReader(commitData), setIdempotencyKey)
+		if execErr == nil && resp.StatusCode() >= 500 {
+			// A 5xx is returned from inside the closure so the Retrier
+			// retries it and the circuit breaker counts it.
+			if mappedErr := mapSignResponseError(resp); mappedErr != nil {
+				return mappedErr
+			}
+			return newServiceError(resp.HTTPResponse, "", http.StatusText(resp.StatusCode()), resp.StatusCode())
+		}
 		return execErr
 	})
 	if err != nil {
@@ -214,6 +472,10 @@ func (c *Client) UploadKey(ctx context.Context, keyID string, armoredPrivateKey
 		}
 	}
 
+	if c.reqSigner != nil {
+		return c.uploadKeySigned(ctx, keyID, armoredPrivateKey)
+	}
+
 	body := api.PostAdminKeysJSONRequestBody{
 		ArmoredPrivateKey: armoredPrivateKey,
 		KeyId:             keyID,
@@ -236,20 +498,18 @@ func (c *Client) UploadKey(ctx context.Context, keyID string, armoredPrivateKey
 		}, nil
 	}
 
-	if resp.JSON400 != nil || resp.JSON500 != nil {
-		errResp := resp.JSON400
-		statusCode := 400
-		if errResp == nil {
-			errResp = resp.JSON500
-			statusCode = 500
-		}
+	if resp.JSON400 != nil {
 		return nil, &ServiceError{
-			Code:       string(errResp.Code),
-			Message:    errResp.Error,
-			StatusCode: statusCode,
+			Code:       string(resp.JSON400.Code),
+			Message:    resp.JSON400.Error,
+			StatusCode: 400,
 		}
 	}
 
+	if resp.JSON500 != nil {
+		return nil, newServiceError(resp.HTTPResponse, string(resp.JSON500.Code), resp.JSON500.Error, 500)
+	}
+
 	return nil, newUnexpectedStatusError(resp.StatusCode())
 }
 
@@ -279,11 +539,7 @@ func (c *Client) ListKeys(ctx context.Context) ([]KeyMetadata, error) {
 	}
 
 	if resp.JSON500 != nil {
-		return nil, &ServiceError{
-			Code:       string(resp.JSON500.Code),
-			Message:    resp.JSON500.Error,
-			StatusCode: 500,
-		}
+		return nil, newServiceError(resp.HTTPResponse, string(resp.JSON500.Code), resp.JSON500.Error, 500)
 	}
 
 	return nil, newUnexpectedStatusError(resp.StatusCode())
@@ -291,6 +547,10 @@ func (c *Client) ListKeys(ctx context.Context) ([]KeyMetadata, error) {
 
 // DeleteKey deletes a signing key (admin operation).
 func (c *Client) DeleteKey(ctx context.Context, keyID string) error {
+	if c.reqSigner != nil {
+		return c.deleteKeySigned(ctx, keyID)
+	}
+
 	var resp *api.DeleteAdminKeysKeyIdResponse
 	err := c.retrier.Do(ctx, func() error {
 		var execErr error
@@ -313,18 +573,21 @@ func (c *Client) DeleteKey(ctx context.Context, keyID string) error {
 	}
 
 	if resp.JSON500 != nil {
-		return &ServiceError{
-			Code:       string(resp.JSON500.Code),
-			Message:    resp.JSON500.Error,
-			StatusCode: 500,
-		}
+		return newServiceError(resp.HTTPResponse, string(resp.JSON500.Code), resp.JSON500.Error, 500)
 	}
 
 	return newUnexpectedStatusError(resp.StatusCode())
 }
 
-// AuditLogs queries audit logs (admin operation).
+// AuditLogs queries audit logs (admin operation). If filter.Follow is
+// set, it upgrades transparently to StreamAuditLogs: rather than
+// returning the first page immediately, it accumulates entries from the
+// stream until ctx is done and returns everything collected.
 func (c *Client) AuditLogs(ctx context.Context, filter AuditFilter) (*AuditResult, error) {
+	if filter.Follow {
+		return c.followAuditLogs(ctx, filter)
+	}
+
 	params := buildAuditParams(filter)
 
 	var resp *api.GetAdminAuditResponse
@@ -348,6 +611,28 @@ func (c *Client) AuditLogs(ctx context.Context, filter AuditFilter) (*AuditResul
 	return nil, newUnexpectedStatusError(resp.StatusCode())
 }
 
+// followAuditLogs backs the filter.Follow path of AuditLogs, collecting
+// entries delivered by StreamAuditLogs until ctx is done.
+func (c *Client) followAuditLogs(ctx context.Context, filter AuditFilter) (*AuditResult, error) {
+	events, errs := c.StreamAuditLogs(ctx, filter)
+
+	result := &AuditResult{}
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return result, nil
+			}
+			result.Logs = append(result.Logs, ev.AuditLog)
+			result.Count++
+		case err := <-errs:
+			return result, err
+		case <-ctx.Done():
+			return result, nil
+		}
+	}
+}
+
 // AdminPublicKey retrieves the public key via the admin endpoint.
 func (c *Client) AdminPublicKey(ctx context.Context, keyID string) (string, error) {
 	if keyID == "" {
@@ -368,7 +653,7 @@ func (c *Client) AdminPublicKey(ctx context.Context, keyID string) (string, erro
 	}
 
 	if resp.StatusCode() == 200 {
-		return string(resp.Body), nil
+		return c.verifyKeyResponse(ctx, resp.HTTPResponse, resp.Body)
 	}
 
 	if resp.JSON404 != nil {
@@ -380,11 +665,7 @@ func (c *Client) AdminPublicKey(ctx context.Context, keyID string) (string, erro
 	}
 
 	if resp.JSON500 != nil {
-		return "", &ServiceError{
-			Code:       string(resp.JSON500.Code),
-			Message:    resp.JSON500.Error,
-			StatusCode: 500,
-		}
+		return "", newServiceError(resp.HTTPResponse, string(resp.JSON500.Code), resp.JSON500.Error, 500)
 	}
 
 	return "", newUnexpectedStatusError(resp.StatusCode())
@@ -395,18 +676,15 @@ func mapAuditResponseError(resp *api.GetAdminAuditResponse) error {
 		return nil
 	}
 
-	errResp := resp.JSON400
-	statusCode := 400
-	if errResp == nil {
-		errResp = resp.JSON500
-		statusCode = 500
+	if resp.JSON400 != nil {
+		return &ServiceError{
+			Code:       string(resp.JSON400.Code),
+			Message:    resp.JSON400.Error,
+			StatusCode: 400,
+		}
 	}
 
-	return &ServiceError{
-		Code:       string(errResp.Code),
-		Message:    errResp.Error,
-		StatusCode: statusCode,
-	}
+	return newServiceError(resp.HTTPResponse, string(resp.JSON500.Code), resp.JSON500.Error, 500)
 }
 
 func parseAuditSuccess(resp *api.GetAdminAuditResponse) (*AuditResult, bool) {
@@ -542,11 +820,7 @@ func mapServerError(resp *api.PostSignResponse) *ServiceError {
 		statusCode = 503
 	}
 
-	serviceErr := &ServiceError{
-		Code:       string(errResp.Code),
-		Message:    errResp.Error,
-		StatusCode: statusCode,
-	}
+	serviceErr := newServiceError(resp.HTTPResponse, string(errResp.Code), errResp.Error, statusCode)
 	if errResp.RequestId != nil {
 		serviceErr.RequestID = errResp.RequestId.String()
 	}
@@ -554,6 +828,24 @@ func mapServerError(resp *api.PostSignResponse) *ServiceError {
 	return serviceErr
 }
 
+// newServiceError builds a ServiceError for a single-response endpoint,
+// populating RetryAfter from httpResp's Retry-After header when present
+// so every retryable response honors it, not just Sign's. httpResp is
+// nil-safe since not every error path has one handy.
+func newServiceError(httpResp *http.Response, code, message string, statusCode int) *ServiceError {
+	serviceErr := &ServiceError{
+		Code:       code,
+		Message:    message,
+		StatusCode: statusCode,
+	}
+	if httpResp != nil {
+		if d, ok := parseRetryAfter(httpResp.Header.Get("Retry-After")); ok {
+			serviceErr.RetryAfter = d
+		}
+	}
+	return serviceErr
+}
+
 func parseRateLimitHeaders(resp *api.PostSignResponse, result *SignResult) {
 	remaining := resp.HTTPResponse.Header.Get("X-RateLimit-Remaining")
 	if remaining != "" {
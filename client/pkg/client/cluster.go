@@ -0,0 +1,437 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// PinFunc computes a stable key from a request's identifying
+// information (e.g. the keyID being used) so that related requests land
+// on the same endpoint, which helps cache-warmth on the backend.
+type PinFunc func(keyID string) string
+
+// ClusterOption configures a Cluster.
+type ClusterOption func(*clusterOptions)
+
+type clusterOptions struct {
+	healthInterval time.Duration
+	pin            PinFunc
+	maxRetries     int
+	backoff        Backoff
+}
+
+func defaultClusterOptions() *clusterOptions {
+	return &clusterOptions{
+		healthInterval: 30 * time.Second,
+		backoff:        ExponentialBackoff{Base: 100 * time.Millisecond, Max: 5 * time.Second},
+	}
+}
+
+// WithHealthCheckInterval sets how often the cluster pings each
+// endpoint's /health in the background to keep the rotation accurate.
+// The default is 30 seconds.
+func WithHealthCheckInterval(d time.Duration) ClusterOption {
+	return func(o *clusterOptions) {
+		o.healthInterval = d
+	}
+}
+
+// WithEndpointPinning pins all requests that share a pin key (as
+// computed by hash) to the same replica, instead of the default sticky-
+// to-first-healthy behavior.
+func WithEndpointPinning(hash PinFunc) ClusterOption {
+	return func(o *clusterOptions) {
+		o.pin = hash
+	}
+}
+
+// WithClusterMaxRetries caps how many endpoints a single call will try
+// before giving up, in addition to rotating away from unhealthy ones.
+// The default is to try every configured endpoint once.
+func WithClusterMaxRetries(n int) ClusterOption {
+	return func(o *clusterOptions) {
+		o.maxRetries = n
+	}
+}
+
+// WithClusterBackoff sets the wait strategy applied between failover
+// attempts. The default is an ExponentialBackoff starting at 100ms and
+// capped at 5s.
+func WithClusterBackoff(b Backoff) ClusterOption {
+	return func(o *clusterOptions) {
+		o.backoff = b
+	}
+}
+
+type endpoint struct {
+	url     string
+	client  *Client
+	healthy bool
+}
+
+// Cluster load-balances and fails over between multiple signing-service
+// endpoints, modeled on etcd's httpClusterClient. By default requests
+// are sticky to the first healthy endpoint and only rotate away from it
+// on a connection error, a 5xx ServiceError, or a transport-originated
+// deadline exceeded (the caller's own ctx.Err() is never treated as a
+// failover signal). A background goroutine periodically health-checks
+// every endpoint and demotes unhealthy ones from the rotation.
+type Cluster struct {
+	opts *clusterOptions
+
+	mu        sync.RWMutex
+	endpoints []*endpoint
+	current   int
+
+	stop chan struct{}
+}
+
+// NewCluster creates a Cluster over the given base URLs, each member
+// client configured identically via opts. Cluster-level behavior (health
+// check interval, endpoint pinning) is configured via clusterOpts.
+func NewCluster(endpoints []string, clusterOpts []ClusterOption, opts ...Option) (*Cluster, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("client: NewCluster requires at least one endpoint")
+	}
+
+	co := defaultClusterOptions()
+	for _, opt := range clusterOpts {
+		opt(co)
+	}
+
+	eps := make([]*endpoint, len(endpoints))
+	for i, url := range endpoints {
+		c, err := New(url, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("client: create cluster member %s: %w", url, err)
+		}
+		eps[i] = &endpoint{url: url, client: c, healthy: true}
+	}
+
+	cl := &Cluster{
+		opts:      co,
+		endpoints: eps,
+		stop:      make(chan struct{}),
+	}
+	go cl.runHealthChecks()
+	return cl, nil
+}
+
+// Close stops the cluster's background health checker and every member
+// client.
+func (cl *Cluster) Close() error {
+	cl.mu.Lock()
+	select {
+	case <-cl.stop:
+	default:
+		close(cl.stop)
+	}
+	eps := cl.endpoints
+	cl.mu.Unlock()
+
+	for _, ep := range eps {
+		_ = ep.client.Close()
+	}
+	return nil
+}
+
+// Sign signs commit data, failing over to another healthy endpoint on a
+// qualifying transport/server error.
+func (cl *Cluster) Sign(ctx context.Context, commitData string, keyID string) (*SignResult, error) {
+	var result *SignResult
+	err := cl.do(ctx, keyID, func(c *Client) error {
+		var signErr error
+		result, signErr = c.Sign(ctx, commitData, keyID)
+		return signErr
+	})
+	return result, err
+}
+
+// Health checks the currently selected endpoint's health.
+func (cl *Cluster) Health(ctx context.Context) (*HealthStatus, error) {
+	var result *HealthStatus
+	err := cl.do(ctx, "", func(c *Client) error {
+		var healthErr error
+		result, healthErr = c.Health(ctx)
+		return healthErr
+	})
+	return result, err
+}
+
+// PublicKey fetches the armored public key for keyID, failing over to
+// another healthy endpoint on a qualifying transport/server error.
+func (cl *Cluster) PublicKey(ctx context.Context, keyID string) (string, error) {
+	var result string
+	err := cl.do(ctx, keyID, func(c *Client) error {
+		var pubErr error
+		result, pubErr = c.PublicKey(ctx, keyID)
+		return pubErr
+	})
+	return result, err
+}
+
+// AdminPublicKey fetches the armored public key for keyID using admin
+// auth, failing over to another healthy endpoint on a qualifying
+// transport/server error.
+func (cl *Cluster) AdminPublicKey(ctx context.Context, keyID string) (string, error) {
+	var result string
+	err := cl.do(ctx, keyID, func(c *Client) error {
+		var pubErr error
+		result, pubErr = c.AdminPublicKey(ctx, keyID)
+		return pubErr
+	})
+	return result, err
+}
+
+// UploadKey uploads a new private key under keyID, failing over to
+// another healthy endpoint on a qualifying transport/server error.
+func (cl *Cluster) UploadKey(ctx context.Context, keyID string, armoredPrivateKey string) (*KeyInfo, error) {
+	var result *KeyInfo
+	err := cl.do(ctx, keyID, func(c *Client) error {
+		var uploadErr error
+		result, uploadErr = c.UploadKey(ctx, keyID, armoredPrivateKey)
+		return uploadErr
+	})
+	return result, err
+}
+
+// DeleteKey deletes keyID, failing over to another healthy endpoint on a
+// qualifying transport/server error.
+func (cl *Cluster) DeleteKey(ctx context.Context, keyID string) error {
+	return cl.do(ctx, keyID, func(c *Client) error {
+		return c.DeleteKey(ctx, keyID)
+	})
+}
+
+// RevokeKey revokes keyID, failing over to another healthy endpoint on a
+// qualifying transport/server error.
+func (cl *Cluster) RevokeKey(ctx context.Context, keyID string, req RevokeKeyRequest) (*RevokeKeyResult, error) {
+	var result *RevokeKeyResult
+	err := cl.do(ctx, keyID, func(c *Client) error {
+		var revokeErr error
+		result, revokeErr = c.RevokeKey(ctx, keyID, req)
+		return revokeErr
+	})
+	return result, err
+}
+
+// AuditLogs queries audit logs matching filter, failing over to another
+// healthy endpoint on a qualifying transport/server error.
+func (cl *Cluster) AuditLogs(ctx context.Context, filter AuditFilter) (*AuditResult, error) {
+	var result *AuditResult
+	err := cl.do(ctx, "", func(c *Client) error {
+		var auditErr error
+		result, auditErr = c.AuditLogs(ctx, filter)
+		return auditErr
+	})
+	return result, err
+}
+
+// ListKeys lists key metadata, failing over to another healthy endpoint
+// on a qualifying transport/server error.
+func (cl *Cluster) ListKeys(ctx context.Context) ([]KeyMetadata, error) {
+	var result []KeyMetadata
+	err := cl.do(ctx, "", func(c *Client) error {
+		var listErr error
+		result, listErr = c.ListKeys(ctx)
+		return listErr
+	})
+	return result, err
+}
+
+// WaitForHealthy polls Health in a loop, failing over between endpoints
+// as cl.Health itself does, sleeping between attempts until the cluster
+// reports healthy or the configured retry timeout elapses.
+func (cl *Cluster) WaitForHealthy(ctx context.Context, opts ...WaitOption) (*HealthWaitResult, error) {
+	o := defaultWaitOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.retryTimeout)
+	defer cancel()
+
+	start := time.Now()
+	attempts := 0
+	var last *HealthStatus
+
+	for {
+		attempts++
+		status, err := cl.Health(ctx)
+		if status != nil {
+			last = status
+		}
+		if err == nil && status != nil && status.IsHealthy() {
+			return &HealthWaitResult{Elapsed: time.Since(start), Attempts: attempts}, nil
+		}
+
+		timer := time.NewTimer(o.sleep)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, &HealthTimeoutError{Last: last}
+		}
+	}
+}
+
+// do runs fn against the selected endpoint, rotating to the next
+// healthy endpoint and retrying, with backoff between attempts, if fn
+// fails with a qualifying error. The number of attempts is capped at
+// WithClusterMaxRetries when set, otherwise at the number of configured
+// endpoints.
+func (cl *Cluster) do(ctx context.Context, pinKey string, fn func(*Client) error) error {
+	endpoints := cl.endpointCount()
+	if endpoints == 0 {
+		return errors.New("client: cluster has no endpoints")
+	}
+
+	attempts := endpoints
+	if cl.opts.maxRetries > 0 {
+		attempts = cl.opts.maxRetries
+	}
+
+	var lastErr error
+	var prevWait time.Duration
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			wait := cl.opts.backoff.Next(i-1, prevWait)
+			prevWait = wait
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		ep := cl.pickEndpoint(pinKey)
+		if ep == nil {
+			if lastErr != nil {
+				return lastErr
+			}
+			return errors.New("client: no healthy endpoint")
+		}
+
+		err := fn(ep.client)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+			// The caller's own context was canceled/expired: not a
+			// failover signal.
+			return err
+		}
+		if !shouldFailover(err) {
+			return err
+		}
+
+		cl.demote(ep)
+	}
+	return lastErr
+}
+
+// shouldFailover reports whether err indicates the current endpoint is
+// unreachable or unhealthy, as opposed to a client-side or request
+// validation failure that would recur on every endpoint.
+func shouldFailover(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	return IsServiceError(err)
+}
+
+func (cl *Cluster) endpointCount() int {
+	cl.mu.RLock()
+	defer cl.mu.RUnlock()
+	return len(cl.endpoints)
+}
+
+// pickEndpoint picks the endpoint to use for the next attempt: pinned by key
+// if WithEndpointPinning is configured, otherwise sticky to the first
+// healthy endpoint.
+func (cl *Cluster) pickEndpoint(pinKey string) *endpoint {
+	cl.mu.RLock()
+	defer cl.mu.RUnlock()
+
+	if cl.opts.pin != nil && pinKey != "" {
+		hash := cl.opts.pin(pinKey)
+		idx := int(hashString(hash)) % len(cl.endpoints)
+		for i := 0; i < len(cl.endpoints); i++ {
+			ep := cl.endpoints[(idx+i)%len(cl.endpoints)]
+			if ep.healthy {
+				return ep
+			}
+		}
+		return nil
+	}
+
+	for i := 0; i < len(cl.endpoints); i++ {
+		ep := cl.endpoints[(cl.current+i)%len(cl.endpoints)]
+		if ep.healthy {
+			return ep
+		}
+	}
+	return nil
+}
+
+// demote marks ep unhealthy and rotates the sticky pointer to the next
+// endpoint in the ring.
+func (cl *Cluster) demote(ep *endpoint) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	ep.healthy = false
+	for i, e := range cl.endpoints {
+		if e == ep {
+			cl.current = (i + 1) % len(cl.endpoints)
+			break
+		}
+	}
+}
+
+func (cl *Cluster) runHealthChecks() {
+	ticker := time.NewTicker(cl.opts.healthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-cl.stop:
+			return
+		case <-ticker.C:
+			cl.checkAll()
+		}
+	}
+}
+
+func (cl *Cluster) checkAll() {
+	cl.mu.RLock()
+	eps := append([]*endpoint(nil), cl.endpoints...)
+	cl.mu.RUnlock()
+
+	for _, ep := range eps {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, err := ep.client.Health(ctx)
+		cancel()
+
+		cl.mu.Lock()
+		ep.healthy = err == nil
+		cl.mu.Unlock()
+	}
+}
+
+func hashString(s string) uint32 {
+	var h uint32 = 2166136261
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= 16777619
+	}
+	return h
+}
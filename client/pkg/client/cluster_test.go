@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestShouldFailoverOnServiceError(t *testing.T) {
+	if !shouldFailover(&ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500}) {
+		t.Error("expected 5xx ServiceError to trigger failover")
+	}
+}
+
+func TestShouldFailoverOnNetError(t *testing.T) {
+	var netErr net.Error = &net.DNSError{Err: "no such host", IsNotFound: true}
+	if !shouldFailover(netErr) {
+		t.Error("expected net.Error to trigger failover")
+	}
+}
+
+func TestShouldNotFailoverOnValidationError(t *testing.T) {
+	if shouldFailover(&ValidationError{Code: "INVALID_REQUEST", Message: "bad input"}) {
+		t.Error("expected ValidationError not to trigger failover")
+	}
+}
+
+func TestClusterDemoteRotatesCurrent(t *testing.T) {
+	cl := &Cluster{
+		opts: defaultClusterOptions(),
+		endpoints: []*endpoint{
+			{url: "a", healthy: true},
+			{url: "b", healthy: true},
+		},
+	}
+
+	ep := cl.pickEndpoint("")
+	if ep.url != "a" {
+		t.Fatalf("expected first endpoint 'a', got %q", ep.url)
+	}
+
+	cl.demote(ep)
+
+	next := cl.pickEndpoint("")
+	if next.url != "b" {
+		t.Fatalf("expected rotation to 'b', got %q", next.url)
+	}
+	if ep.healthy {
+		t.Error("expected demoted endpoint to be unhealthy")
+	}
+}
+
+func TestClusterDoFailsOverAndRecoversAfterTransientErrors(t *testing.T) {
+	cl := &Cluster{
+		opts: &clusterOptions{backoff: ConstantBackoff{}},
+		endpoints: []*endpoint{
+			{url: "a", healthy: true, client: &Client{}},
+			{url: "b", healthy: true, client: &Client{}},
+			{url: "c", healthy: true, client: &Client{}},
+		},
+	}
+
+	failuresLeft := 2
+	var sawEndpoints []string
+	err := cl.do(context.Background(), "", func(c *Client) error {
+		for _, ep := range cl.endpoints {
+			if ep.client == c {
+				sawEndpoints = append(sawEndpoints, ep.url)
+			}
+		}
+		if failuresLeft > 0 {
+			failuresLeft--
+			return &ServiceError{Code: "INTERNAL_ERROR", StatusCode: 503}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if want := []string{"a", "b", "c"}; len(sawEndpoints) != len(want) || sawEndpoints[0] != want[0] || sawEndpoints[1] != want[1] || sawEndpoints[2] != want[2] {
+		t.Errorf("sawEndpoints = %v, want %v", sawEndpoints, want)
+	}
+}
+
+func TestClusterDoRespectsMaxRetries(t *testing.T) {
+	cl := &Cluster{
+		opts: &clusterOptions{backoff: ConstantBackoff{}, maxRetries: 2},
+		endpoints: []*endpoint{
+			{url: "a", healthy: true, client: &Client{}},
+			{url: "b", healthy: true, client: &Client{}},
+			{url: "c", healthy: true, client: &Client{}},
+		},
+	}
+
+	attempts := 0
+	err := cl.do(context.Background(), "", func(*Client) error {
+		attempts++
+		return &ServiceError{Code: "INTERNAL_ERROR", StatusCode: 503}
+	})
+	if err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2 (capped by WithClusterMaxRetries)", attempts)
+	}
+}
+
+func TestClusterDoReturnsClientContextErrorWithoutFailover(t *testing.T) {
+	cl := &Cluster{
+		opts: defaultClusterOptions(),
+		endpoints: []*endpoint{
+			{url: "a", healthy: true, client: &Client{}},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := cl.do(ctx, "", func(*Client) error {
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if !cl.endpoints[0].healthy {
+		t.Error("expected endpoint to remain healthy after caller-context cancellation")
+	}
+}
+
+func TestClusterDoReturnsErrorWhenNoEndpointIsHealthy(t *testing.T) {
+	cl := &Cluster{
+		opts: &clusterOptions{backoff: ConstantBackoff{}},
+		endpoints: []*endpoint{
+			{url: "a", healthy: false, client: &Client{}},
+			{url: "b", healthy: false, client: &Client{}},
+		},
+	}
+
+	called := false
+	err := cl.do(context.Background(), "", func(*Client) error {
+		called = true
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected a non-nil error when every endpoint is unhealthy")
+	}
+	if called {
+		t.Error("fn should not be called when pickEndpoint has nothing to return")
+	}
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// wellKnownDirectoryPath is where Discover looks for the service's
+// capability directory, following the ACME-style ".well-known" convention
+// (see golang.org/x/crypto/acme's Client.Discover).
+const wellKnownDirectoryPath = "/.well-known/gpg-signing-service"
+
+// ServiceMeta describes server-advertised capabilities returned by
+// Discover, so callers can adapt before sending a request (e.g. reject an
+// unsupported algorithm locally) instead of discovering a mismatch from
+// an error response.
+type ServiceMeta struct {
+	SupportedAlgorithms []string
+	MaxPayloadSize      int64
+	RateLimitWindow     time.Duration
+	MinRetryAfter       time.Duration
+	MaxRetryAfter       time.Duration
+}
+
+// directoryDoc is the JSON document served at wellKnownDirectoryPath.
+type directoryDoc struct {
+	Sign      string `json:"sign"`
+	PublicKey string `json:"publicKey"`
+	Keys      string `json:"keys"`
+	AuditLogs string `json:"auditLogs"`
+	Health    string `json:"health"`
+	Meta      struct {
+		SupportedAlgorithms []string `json:"supportedAlgorithms"`
+		MaxPayloadSize      int64    `json:"maxPayloadSize"`
+		RateLimitWindowSecs int64    `json:"rateLimitWindowSeconds"`
+		MinRetryAfterSecs   int64    `json:"minRetryAfterSeconds"`
+		MaxRetryAfterSecs   int64    `json:"maxRetryAfterSeconds"`
+	} `json:"meta"`
+}
+
+// serviceDirectory is the client's cached, parsed view of a directoryDoc.
+type serviceDirectory struct {
+	urls map[string]string
+	meta ServiceMeta
+}
+
+// Discover fetches the service's capability directory from
+// <baseURL>/.well-known/gpg-signing-service and caches it, so Meta can
+// report server-advertised limits without an extra round-trip per call.
+//
+// The generated API client (client/pkg/api) bakes in fixed REST paths for
+// Health, PublicKey, Sign, and the admin endpoints, so the URLs in the
+// directory are not yet used to re-route those methods: Discover is a
+// capability-negotiation mechanism today, not a full endpoint override.
+func (c *Client) Discover(ctx context.Context) error {
+	dir, err := c.fetchDirectory(ctx)
+	if err != nil {
+		return err
+	}
+	c.directoryMu.Lock()
+	c.directory = dir
+	c.directoryMu.Unlock()
+	return nil
+}
+
+func (c *Client) fetchDirectory(ctx context.Context) (*serviceDirectory, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+wellKnownDirectoryPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	if h := c.authHeader(); h != "" {
+		req.Header.Set("Authorization", h)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch directory: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch directory: unexpected status %d", resp.StatusCode)
+	}
+
+	var doc directoryDoc
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode directory: %w", err)
+	}
+
+	return &serviceDirectory{
+		urls: map[string]string{
+			"sign":      doc.Sign,
+			"publicKey": doc.PublicKey,
+			"keys":      doc.Keys,
+			"auditLogs": doc.AuditLogs,
+			"health":    doc.Health,
+		},
+		meta: ServiceMeta{
+			SupportedAlgorithms: doc.Meta.SupportedAlgorithms,
+			MaxPayloadSize:      doc.Meta.MaxPayloadSize,
+			RateLimitWindow:     time.Duration(doc.Meta.RateLimitWindowSecs) * time.Second,
+			MinRetryAfter:       time.Duration(doc.Meta.MinRetryAfterSecs) * time.Second,
+			MaxRetryAfter:       time.Duration(doc.Meta.MaxRetryAfterSecs) * time.Second,
+		},
+	}, nil
+}
+
+// Meta returns the server-advertised capabilities from the last
+// successful Discover call, or nil if Discover has never been called (or
+// its last call failed).
+func (c *Client) Meta() *ServiceMeta {
+	c.directoryMu.Lock()
+	defer c.directoryMu.Unlock()
+	if c.directory == nil {
+		return nil
+	}
+	meta := c.directory.meta
+	return &meta
+}
+
+// resolvedURL returns the directory-advertised URL for name ("sign",
+// "publicKey", "keys", "auditLogs", or "health"), fetching the directory
+// first if it has not been cached yet.
+func (c *Client) resolvedURL(ctx context.Context, name string) (string, error) {
+	c.directoryMu.Lock()
+	dir := c.directory
+	c.directoryMu.Unlock()
+
+	if dir == nil {
+		var err error
+		dir, err = c.fetchDirectory(ctx)
+		if err != nil {
+			return "", err
+		}
+		c.directoryMu.Lock()
+		c.directory = dir
+		c.directoryMu.Unlock()
+	}
+
+	url, ok := dir.urls[name]
+	if !ok || url == "" {
+		return "", fmt.Errorf("directory: no URL advertised for %q", name)
+	}
+	return url, nil
+}
+
+// RefreshDirectory discards the cached directory so the next Discover or
+// resolvedURL call re-fetches it. Callers that drive their own requests
+// against a resolvedURL should call this after observing a 404 or 410,
+// since the service may have moved the endpoint.
+func (c *Client) RefreshDirectory() {
+	c.directoryMu.Lock()
+	c.directory = nil
+	c.directoryMu.Unlock()
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func directoryDocJSON() string {
+	return `{
+		"sign": "/sign",
+		"publicKey": "/publicKey",
+		"keys": "/admin/keys",
+		"auditLogs": "/admin/audit",
+		"health": "/health",
+		"meta": {
+			"supportedAlgorithms": ["rsa4096", "ed25519"],
+			"maxPayloadSize": 1048576,
+			"rateLimitWindowSeconds": 60,
+			"minRetryAfterSeconds": 1,
+			"maxRetryAfterSeconds": 30
+		}
+	}`
+}
+
+func TestDiscoverCachesMeta(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != wellKnownDirectoryPath {
+			t.Errorf("expected %s, got %s", wellKnownDirectoryPath, r.URL.Path)
+		}
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(directoryDocJSON()))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if meta := c.Meta(); meta != nil {
+		t.Errorf("expected nil Meta before Discover, got %+v", meta)
+	}
+
+	if err := c.Discover(context.Background()); err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	meta := c.Meta()
+	if meta == nil {
+		t.Fatal("expected non-nil Meta after Discover")
+	}
+	if len(meta.SupportedAlgorithms) != 2 || meta.SupportedAlgorithms[0] != "rsa4096" {
+		t.Errorf("unexpected SupportedAlgorithms: %v", meta.SupportedAlgorithms)
+	}
+	if meta.MaxPayloadSize != 1048576 {
+		t.Errorf("MaxPayloadSize = %d, want 1048576", meta.MaxPayloadSize)
+	}
+	if meta.RateLimitWindow != 60*time.Second {
+		t.Errorf("RateLimitWindow = %v, want 60s", meta.RateLimitWindow)
+	}
+
+	if requests != 1 {
+		t.Errorf("expected 1 request to the directory, got %d", requests)
+	}
+}
+
+func TestResolvedURLFetchesOnFirstUse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(directoryDocJSON()))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	url, err := c.resolvedURL(context.Background(), "sign")
+	if err != nil {
+		t.Fatalf("resolvedURL: %v", err)
+	}
+	if url != "/sign" {
+		t.Errorf("resolvedURL(sign) = %q, want /sign", url)
+	}
+
+	if _, err := c.resolvedURL(context.Background(), "nope"); err == nil {
+		t.Error("expected error for unknown directory entry")
+	}
+}
+
+func TestRefreshDirectoryForcesRefetch(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(directoryDocJSON()))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := c.Discover(context.Background()); err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	c.RefreshDirectory()
+	if _, err := c.resolvedURL(context.Background(), "sign"); err != nil {
+		t.Fatalf("resolvedURL: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("expected 2 requests after RefreshDirectory, got %d", requests)
+	}
+}
+
+func TestDiscoverRejectsNon200(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := c.Discover(context.Background()); err == nil {
+		t.Error("expected error for non-200 directory response")
+	}
+}
+
+func TestDiscoverRejectsInvalidJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("not json"))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := c.Discover(context.Background()); err == nil {
+		t.Error("expected error for invalid directory JSON")
+	}
+}
@@ -10,6 +10,7 @@ import (
 const (
 	ErrCodeDegraded    = "SERVICE_DEGRADED"
 	ErrCodeKeyNotFound = "KEY_NOT_FOUND"
+	ErrCodeJobNotFound = "JOB_NOT_FOUND"
 )
 
 // Common errors
@@ -17,12 +18,30 @@ var (
 	ErrUnexpectedStatus = errors.New("unexpected status code")
 )
 
+// Sentinel errors for use with errors.Is, matched by the Is method of
+// the concrete typed error that represents each condition. They carry
+// no data of their own; errors.As (or a type assertion) is still the
+// way to get at a typed error's Code, Message, etc.
+var (
+	ErrKeyNotFound        = errors.New("key not found")
+	ErrRateLimited        = errors.New("rate limited")
+	ErrAuthFailed         = errors.New("authentication failed")
+	ErrValidation         = errors.New("validation error")
+	ErrServiceUnavailable = errors.New("service unavailable")
+)
+
 // ServiceError represents an API error response.
 type ServiceError struct {
 	Code       string
 	Message    string
 	StatusCode int
 	RequestID  string
+
+	// RetryAfter is the server-supplied delay from a Retry-After response
+	// header, or zero if none was sent or parseable. The Retrier prefers
+	// this over its computed backoff when set. Populated by newServiceError
+	// for every retryable (5xx) response this package constructs.
+	RetryAfter time.Duration
 }
 
 func (e *ServiceError) Error() string {
@@ -32,6 +51,54 @@ func (e *ServiceError) Error() string {
 	return fmt.Sprintf("%s: %s (status %d)", e.Code, e.Message, e.StatusCode)
 }
 
+// Is reports whether target is one of the sentinels ServiceError can
+// represent: ErrKeyNotFound when Code is ErrCodeKeyNotFound, or
+// ErrServiceUnavailable for any 5xx status.
+func (e *ServiceError) Is(target error) bool {
+	switch target {
+	case ErrKeyNotFound:
+		return e.Code == ErrCodeKeyNotFound
+	case ErrServiceUnavailable:
+		return e.StatusCode >= 500
+	default:
+		return false
+	}
+}
+
+// RetryAbortedError is returned by the Retrier when ctx is canceled (or
+// its deadline expires) while waiting between retry attempts, instead of
+// surfacing the bare ctx.Err(). It carries the error the last attempt
+// actually failed with, so callers don't just see "context canceled"
+// with no idea what was being retried.
+type RetryAbortedError struct {
+	// LastErr is the error from the most recent attempt before
+	// cancellation.
+	LastErr error
+	// Attempts is the number of attempts already made.
+	Attempts int
+	// Elapsed is the sum of the wait durations the Retrier attempted to
+	// sleep for before giving up, including the one interrupted by
+	// cancellation.
+	Elapsed time.Duration
+	// CtxErr is the error returned by ctx.Err(), e.g. context.Canceled or
+	// context.DeadlineExceeded.
+	CtxErr error
+}
+
+func (e *RetryAbortedError) Error() string {
+	return fmt.Sprintf("retry aborted after %d attempt(s), %v elapsed (%v): last error: %v",
+		e.Attempts, e.Elapsed, e.CtxErr, e.LastErr)
+}
+
+// Unwrap exposes both CtxErr and LastErr, so errors.Is(err,
+// context.Canceled) keeps working on a *RetryAbortedError exactly as it
+// did on the bare ctx.Err() this type replaces, while errors.As can still
+// reach whatever typed error (ServiceError, RateLimitError, ...) the
+// last attempt failed with.
+func (e *RetryAbortedError) Unwrap() []error {
+	return []error{e.CtxErr, e.LastErr}
+}
+
 // AuthError represents authentication failures.
 type AuthError struct {
 	Code    string
@@ -42,6 +109,11 @@ func (e *AuthError) Error() string {
 	return fmt.Sprintf("authentication failed: %s", e.Message)
 }
 
+// Is reports whether target is ErrAuthFailed.
+func (e *AuthError) Is(target error) bool {
+	return target == ErrAuthFailed
+}
+
 // RateLimitError represents rate limit exceeded.
 type RateLimitError struct {
 	Message    string
@@ -55,6 +127,11 @@ func (e *RateLimitError) Error() string {
 	return fmt.Sprintf("rate limited: %s", e.Message)
 }
 
+// Is reports whether target is ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
 // ValidationError represents invalid request data.
 type ValidationError struct {
 	Code    string
@@ -65,34 +142,34 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("validation error: %s", e.Message)
 }
 
+// Is reports whether target is ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
 // IsKeyNotFound returns true if the error indicates a key was not found.
 func IsKeyNotFound(err error) bool {
-	var se *ServiceError
-	return errors.As(err, &se) && se.Code == ErrCodeKeyNotFound
+	return errors.Is(err, ErrKeyNotFound)
 }
 
 // IsAuthError returns true if the error is authentication-related.
 func IsAuthError(err error) bool {
-	var ae *AuthError
-	return errors.As(err, &ae)
+	return errors.Is(err, ErrAuthFailed)
 }
 
 // IsRateLimitError returns true if the error indicates rate limit exceeded.
 func IsRateLimitError(err error) bool {
-	var re *RateLimitError
-	return errors.As(err, &re)
+	return errors.Is(err, ErrRateLimited)
 }
 
 // IsValidationError returns true if the error indicates invalid request data.
 func IsValidationError(err error) bool {
-	var ve *ValidationError
-	return errors.As(err, &ve)
+	return errors.Is(err, ErrValidation)
 }
 
 // IsServiceError returns true if the error is a service-side error (5xx).
 func IsServiceError(err error) bool {
-	var se *ServiceError
-	return errors.As(err, &se) && se.StatusCode >= 500
+	return errors.Is(err, ErrServiceUnavailable)
 }
 
 func newUnexpectedStatusError(code int) error {
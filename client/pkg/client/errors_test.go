@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -140,6 +141,42 @@ func TestRateLimitError(t *testing.T) {
 	}
 }
 
+// TestRetryAbortedError tests RetryAbortedError's message, Unwrap chain,
+// and errors.As recovery of the wrapped attempt/elapsed/last-error data.
+func TestRetryAbortedError(t *testing.T) {
+	lastErr := &ServiceError{Code: "INTERNAL_ERROR", Message: "overloaded", StatusCode: 500}
+	err := &RetryAbortedError{
+		LastErr:  lastErr,
+		Attempts: 3,
+		Elapsed:  7 * time.Second,
+		CtxErr:   context.Canceled,
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Error("expected errors.Is(err, context.Canceled) to be true via Unwrap")
+	}
+
+	var abortedErr *RetryAbortedError
+	if !errors.As(err, &abortedErr) {
+		t.Fatal("failed to unwrap RetryAbortedError with errors.As")
+	}
+	if abortedErr.Attempts != 3 {
+		t.Errorf("expected Attempts 3, got %d", abortedErr.Attempts)
+	}
+	if abortedErr.Elapsed != 7*time.Second {
+		t.Errorf("expected Elapsed 7s, got %v", abortedErr.Elapsed)
+	}
+
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) {
+		t.Fatal("expected errors.As to reach the wrapped ServiceError through LastErr")
+	}
+
+	if !contains(err.Error(), "3 attempt") {
+		t.Errorf("expected error message to mention attempt count: %s", err.Error())
+	}
+}
+
 // TestValidationError tests ValidationError type
 func TestValidationError(t *testing.T) {
 	tests := []struct {
@@ -451,6 +488,50 @@ func TestErrorWrapping(t *testing.T) {
 	}
 }
 
+// TestErrorWrappingMultiLevel tests errors.Is and errors.As through
+// several layers of fmt.Errorf("...: %w", ...) wrapping, against both
+// sentinel values and concrete typed errors.
+func TestErrorWrappingMultiLevel(t *testing.T) {
+	keyNotFound := &ServiceError{Code: ErrCodeKeyNotFound, Message: "no such key", StatusCode: 404}
+	wrapped := fmt.Errorf("uploading signature: %w", fmt.Errorf("looking up key: %w", keyNotFound))
+
+	if !errors.Is(wrapped, ErrKeyNotFound) {
+		t.Error("expected errors.Is(wrapped, ErrKeyNotFound) to be true")
+	}
+	if errors.Is(wrapped, ErrServiceUnavailable) {
+		t.Error("expected errors.Is(wrapped, ErrServiceUnavailable) to be false for a 404")
+	}
+
+	var se *ServiceError
+	if !errors.As(wrapped, &se) {
+		t.Fatal("failed to unwrap *ServiceError through multi-level wrapping")
+	}
+	if se.Code != ErrCodeKeyNotFound {
+		t.Errorf("expected Code %q, got %q", ErrCodeKeyNotFound, se.Code)
+	}
+
+	authErr := &AuthError{Code: "EXPIRED", Message: "token expired"}
+	wrappedAuth := fmt.Errorf("refreshing session: %w", fmt.Errorf("authenticating: %w", authErr))
+
+	if !errors.Is(wrappedAuth, ErrAuthFailed) {
+		t.Error("expected errors.Is(wrappedAuth, ErrAuthFailed) to be true")
+	}
+	var ae *AuthError
+	if !errors.As(wrappedAuth, &ae) || ae.Code != "EXPIRED" {
+		t.Error("failed to unwrap *AuthError through multi-level wrapping")
+	}
+
+	unavailable := &ServiceError{Code: ErrCodeDegraded, Message: "degraded", StatusCode: 503}
+	wrappedUnavailable := fmt.Errorf("signing commit: %w", unavailable)
+
+	if !errors.Is(wrappedUnavailable, ErrServiceUnavailable) {
+		t.Error("expected errors.Is(wrappedUnavailable, ErrServiceUnavailable) to be true for a 503")
+	}
+	if errors.Is(wrappedUnavailable, ErrKeyNotFound) {
+		t.Error("expected errors.Is(wrappedUnavailable, ErrKeyNotFound) to be false")
+	}
+}
+
 // TestErrorTypeAssertions tests type assertions for all error types
 func TestErrorTypeAssertions(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrHealthTimeout is returned by WaitForHealthy when the total timeout
+// elapses before the service reports healthy. Use errors.As to recover
+// the last observed HealthStatus.
+var ErrHealthTimeout = errors.New("timed out waiting for service to become healthy")
+
+// HealthTimeoutError wraps ErrHealthTimeout with the last HealthStatus
+// observed before giving up, which may be nil if every attempt errored.
+type HealthTimeoutError struct {
+	Last *HealthStatus
+}
+
+func (e *HealthTimeoutError) Error() string {
+	if e.Last == nil {
+		return ErrHealthTimeout.Error()
+	}
+	return fmt.Sprintf("%s (last status: %s)", ErrHealthTimeout.Error(), e.Last.Status)
+}
+
+func (e *HealthTimeoutError) Unwrap() error {
+	return ErrHealthTimeout
+}
+
+// WaitOption configures WaitForHealthy.
+type WaitOption func(*waitOptions)
+
+type waitOptions struct {
+	sleep        time.Duration
+	retryTimeout time.Duration
+}
+
+func defaultWaitOptions() *waitOptions {
+	return &waitOptions{
+		sleep:        2 * time.Second,
+		retryTimeout: 60 * time.Second,
+	}
+}
+
+// WithHealthSleep sets how long WaitForHealthy sleeps between polls.
+// The default is 2 seconds.
+func WithHealthSleep(d time.Duration) WaitOption {
+	return func(o *waitOptions) {
+		o.sleep = d
+	}
+}
+
+// WithHealthRetryTimeout sets the total time WaitForHealthy will spend
+// polling before giving up. The default is 60 seconds.
+func WithHealthRetryTimeout(total time.Duration) WaitOption {
+	return func(o *waitOptions) {
+		o.retryTimeout = total
+	}
+}
+
+// HealthWaitResult reports how long WaitForHealthy took to observe a
+// healthy service.
+type HealthWaitResult struct {
+	Elapsed  time.Duration
+	Attempts int
+}
+
+// WaitForHealthy polls Health in a loop, sleeping between attempts,
+// until the service reports healthy or the configured retry timeout
+// elapses. It is intended for deployment orchestration (init containers,
+// readiness gates) that need to block until the signing service is up.
+func (c *Client) WaitForHealthy(ctx context.Context, opts ...WaitOption) (*HealthWaitResult, error) {
+	o := defaultWaitOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.retryTimeout)
+	defer cancel()
+
+	start := time.Now()
+	attempts := 0
+	var last *HealthStatus
+
+	for {
+		attempts++
+		status, err := c.Health(ctx)
+		if status != nil {
+			last = status
+		}
+		if err == nil && status != nil && status.IsHealthy() {
+			return &HealthWaitResult{Elapsed: time.Since(start), Attempts: attempts}, nil
+		}
+
+		timer := time.NewTimer(o.sleep)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, &HealthTimeoutError{Last: last}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"container/list"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WithIdempotencyKey overrides the UUIDv4 Idempotency-Key Sign
+// generates automatically for a single call, e.g. to reuse a key a
+// caller persisted across process restarts.
+func WithIdempotencyKey(key string) SignOption {
+	return func(o *signCallOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// WithIdempotencyCache enables a bounded, in-memory cache of Sign
+// results keyed by (Idempotency-Key, request hash), so a caller that
+// retries a logically identical Sign call - same key, same commitData
+// and keyID - gets back the original SignResult without a network round
+// trip. Entries older than ttl are treated as a miss; the least
+// recently used entry is evicted once the cache holds maxSize entries.
+// Only successful results are cached; errors (including ValidationError)
+// never are.
+func WithIdempotencyCache(maxSize int, ttl time.Duration) Option {
+	if maxSize <= 0 {
+		maxSize = 128
+	}
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+	return func(o *Options) {
+		o.idempotencyCache = newIdempotencyCache(maxSize, ttl)
+	}
+}
+
+type idempotencyCacheEntry struct {
+	cacheKey  string
+	result    SignResult
+	expiresAt time.Time
+}
+
+// idempotencyCache is a bounded LRU of SignResult, safe for concurrent
+// use by multiple goroutines.
+type idempotencyCache struct {
+	maxSize int
+	ttl     time.Duration
+
+	mu       sync.Mutex
+	order    *list.List
+	elements map[string]*list.Element
+}
+
+func newIdempotencyCache(maxSize int, ttl time.Duration) *idempotencyCache {
+	return &idempotencyCache{
+		maxSize:  maxSize,
+		ttl:      ttl,
+		order:    list.New(),
+		elements: make(map[string]*list.Element),
+	}
+}
+
+func (c *idempotencyCache) get(idempotencyKey, requestHash string) (SignResult, bool) {
+	key := idempotencyKey + "|" + requestHash
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.elements[key]
+	if !ok {
+		return SignResult{}, false
+	}
+
+	entry := el.Value.(*idempotencyCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(el)
+		delete(c.elements, key)
+		return SignResult{}, false
+	}
+
+	c.order.MoveToFront(el)
+	return entry.result, true
+}
+
+func (c *idempotencyCache) put(idempotencyKey, requestHash string, result SignResult) {
+	key := idempotencyKey + "|" + requestHash
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.elements[key]; ok {
+		entry := el.Value.(*idempotencyCacheEntry)
+		entry.result = result
+		entry.expiresAt = time.Now().Add(c.ttl)
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&idempotencyCacheEntry{
+		cacheKey:  key,
+		result:    result,
+		expiresAt: time.Now().Add(c.ttl),
+	})
+	c.elements[key] = el
+
+	for c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+		c.order.Remove(oldest)
+		delete(c.elements, oldest.Value.(*idempotencyCacheEntry).cacheKey)
+	}
+}
+
+// requestHash fingerprints the caller-visible inputs to a Sign call, so
+// the cache can't return a stale signature for different commitData or
+// keyID that happens to reuse the same Idempotency-Key.
+func requestHash(commitData, keyID string) string {
+	sum := sha256.Sum256([]byte(keyID + "\x00" + commitData))
+	return hex.EncodeToString(sum[:])
+}
+
+// newIdempotencyKey generates a random UUIDv4 per RFC 4122.
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("client: generate idempotency key: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSignRetriesCarrySameIdempotencyKey(t *testing.T) {
+	var keys []string
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		attempts++
+		if attempts < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("signature-data"))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithOIDCToken("token"), WithRetryWait(time.Millisecond, 10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := c.Sign(context.Background(), "commit data", ""); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(keys))
+	}
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("expected the same non-empty Idempotency-Key across retries, got %v", keys)
+	}
+}
+
+func TestSignHonorsExplicitIdempotencyKey(t *testing.T) {
+	var gotKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("Idempotency-Key")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("sig"))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithOIDCToken("token"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := c.Sign(context.Background(), "commit data", "", WithIdempotencyKey("caller-key-1")); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if gotKey != "caller-key-1" {
+		t.Errorf("Idempotency-Key = %q, want %q", gotKey, "caller-key-1")
+	}
+}
+
+func TestSignIdempotencyCacheShortCircuitsSecondCall(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("cached-signature"))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithOIDCToken("token"), WithIdempotencyCache(16, time.Minute))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	result1, err := c.Sign(context.Background(), "commit data", "", WithIdempotencyKey("fixed-key"))
+	if err != nil {
+		t.Fatalf("first Sign: %v", err)
+	}
+	result2, err := c.Sign(context.Background(), "commit data", "", WithIdempotencyKey("fixed-key"))
+	if err != nil {
+		t.Fatalf("second Sign: %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("expected 1 network request, got %d", requests)
+	}
+	if result2.Signature != result1.Signature {
+		t.Errorf("cached result %q != original %q", result2.Signature, result1.Signature)
+	}
+}
+
+func TestSignIdempotencyCacheMissesOnDifferentRequestHash(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("sig"))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithOIDCToken("token"), WithIdempotencyCache(16, time.Minute))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := c.Sign(context.Background(), "commit data A", "", WithIdempotencyKey("fixed-key")); err != nil {
+		t.Fatalf("first Sign: %v", err)
+	}
+	if _, err := c.Sign(context.Background(), "commit data B", "", WithIdempotencyKey("fixed-key")); err != nil {
+		t.Fatalf("second Sign: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("expected 2 network requests for different commitData sharing a key, got %d", requests)
+	}
+}
+
+func TestSignIdempotencyCacheDoesNotCacheValidationError(t *testing.T) {
+	c, err := New("http://example.invalid", WithOIDCToken("token"), WithIdempotencyCache(16, time.Minute))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	_, err = c.Sign(context.Background(), "", "", WithIdempotencyKey("fixed-key"))
+	if !IsValidationError(err) {
+		t.Fatalf("expected ValidationError for empty commitData, got %v", err)
+	}
+
+	if _, ok := c.opts.idempotencyCache.get("fixed-key", requestHash("", "")); ok {
+		t.Error("expected ValidationError not to be cached")
+	}
+}
+
+func TestIdempotencyCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newIdempotencyCache(2, time.Hour)
+
+	cache.put("k1", "h1", SignResult{Signature: "s1"})
+	cache.put("k2", "h2", SignResult{Signature: "s2"})
+
+	// Touch k1 so it becomes more recently used than k2.
+	if _, ok := cache.get("k1", "h1"); !ok {
+		t.Fatal("expected k1 to be present before eviction")
+	}
+
+	cache.put("k3", "h3", SignResult{Signature: "s3"})
+
+	if _, ok := cache.get("k2", "h2"); ok {
+		t.Error("expected k2 (least recently used) to be evicted")
+	}
+	if _, ok := cache.get("k1", "h1"); !ok {
+		t.Error("expected k1 to survive eviction")
+	}
+	if _, ok := cache.get("k3", "h3"); !ok {
+		t.Error("expected k3 to be present")
+	}
+}
+
+func TestIdempotencyCacheExpiresEntriesAfterTTL(t *testing.T) {
+	cache := newIdempotencyCache(16, time.Millisecond)
+	cache.put("k1", "h1", SignResult{Signature: "s1"})
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := cache.get("k1", "h1"); ok {
+		t.Error("expected entry to have expired")
+	}
+}
+
+func TestNewIdempotencyKeyGeneratesDistinctUUIDv4(t *testing.T) {
+	a, err := newIdempotencyKey()
+	if err != nil {
+		t.Fatalf("newIdempotencyKey: %v", err)
+	}
+	b, err := newIdempotencyKey()
+	if err != nil {
+		t.Fatalf("newIdempotencyKey: %v", err)
+	}
+	if a == b {
+		t.Error("expected two generated keys to differ")
+	}
+	if len(a) != 36 {
+		t.Errorf("expected a 36-character UUID string, got %q (%d)", a, len(a))
+	}
+}
@@ -0,0 +1,321 @@
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultJWKSPath is appended to the client's base URL when WithJWKS is
+// given an empty url.
+const defaultJWKSPath = "/.well-known/jwks.json"
+
+// jwksMinRefreshInterval floors how often a kid miss can trigger a
+// refetch of the JWKS document, so a request referencing an unknown or
+// stale kid can't be used to hammer the server.
+const jwksMinRefreshInterval = 30 * time.Second
+
+// KeyVerificationError is returned by PublicKey and AdminPublicKey when
+// the client is configured with WithJWKS and the server's response is
+// unsigned, signed by a kid absent from the JWKS document, or fails
+// signature verification.
+type KeyVerificationError struct {
+	Message string
+}
+
+func (e *KeyVerificationError) Error() string {
+	return fmt.Sprintf("key verification failed: %s", e.Message)
+}
+
+// IsKeyVerificationError returns true if err indicates a public key
+// response failed JWKS signature verification.
+func IsKeyVerificationError(err error) bool {
+	var kv *KeyVerificationError
+	return errors.As(err, &kv)
+}
+
+// WithJWKS enables JWKS-backed signature verification of PublicKey and
+// AdminPublicKey responses, so a key swapped in transit is detected
+// instead of silently trusted. Pass an empty url to fetch the JWKS
+// document from "<baseURL>/.well-known/jwks.json"; otherwise the JWKS
+// document is fetched from url as given. The server must wrap its
+// PublicKey/AdminPublicKey responses in a detached signature, either via
+// an X-Key-Signature response header ("<kid>.<alg>.<sig>") or a JSON
+// envelope ({"key", "sig", "kid"}).
+func WithJWKS(url string) Option {
+	return func(o *Options) {
+		o.jwksURL = &url
+	}
+}
+
+// WithJWKSCache overrides how long a fetched JWKS document is trusted
+// before being re-fetched, taking precedence over the document's own
+// Cache-Control max-age. Only meaningful combined with WithJWKS.
+func WithJWKSCache(ttl time.Duration) Option {
+	return func(o *Options) {
+		o.jwksCacheTTL = ttl
+	}
+}
+
+// jwksVerifier fetches and caches the server's signing JWKS document and
+// verifies detached signatures over PublicKey/AdminPublicKey responses
+// against it.
+type jwksVerifier struct {
+	url      string
+	cacheTTL time.Duration // 0 defers to the document's own Cache-Control max-age
+
+	mu            sync.Mutex
+	keys          map[string]jwksKey
+	etag          string
+	expiresAt     time.Time
+	lastRefreshed time.Time
+}
+
+type jwksKey struct {
+	pub crypto.PublicKey
+}
+
+func newJWKSVerifier(url string, cacheTTL time.Duration) *jwksVerifier {
+	return &jwksVerifier{url: url, cacheTTL: cacheTTL}
+}
+
+type jwkDoc struct {
+	Keys []jwkEntry `json:"keys"`
+}
+
+type jwkEntry struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	Kid string `json:"kid"`
+}
+
+// keyFor returns the cached public key for kid, refreshing the JWKS
+// document first if the cache has expired. A kid miss against a fresh
+// cache also triggers a refetch, but at most once per
+// jwksMinRefreshInterval, so a flood of requests for an unknown kid
+// can't be used to hammer the server.
+func (v *jwksVerifier) keyFor(ctx context.Context, httpClient *http.Client, kid string) (jwksKey, error) {
+	v.mu.Lock()
+	key, ok := v.keys[kid]
+	stale := time.Now().After(v.expiresAt)
+	canRefetch := time.Since(v.lastRefreshed) >= jwksMinRefreshInterval
+	v.mu.Unlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+	if !stale && !canRefetch {
+		return jwksKey{}, fmt.Errorf("jwks: no key found for kid %q", kid)
+	}
+
+	if err := v.refresh(ctx, httpClient); err != nil {
+		if ok {
+			return key, nil
+		}
+		return jwksKey{}, err
+	}
+
+	v.mu.Lock()
+	key, ok = v.keys[kid]
+	v.mu.Unlock()
+	if !ok {
+		return jwksKey{}, fmt.Errorf("jwks: no key found for kid %q", kid)
+	}
+	return key, nil
+}
+
+// refresh re-fetches the JWKS document, sending an If-None-Match
+// revalidation request when an ETag from a prior fetch is known.
+func (v *jwksVerifier) refresh(ctx context.Context, httpClient *http.Client) error {
+	v.mu.Lock()
+	etag := v.etag
+	v.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url, nil)
+	if err != nil {
+		return err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("jwks: fetch %s: %w", v.url, err)
+	}
+	defer resp.Body.Close()
+
+	v.mu.Lock()
+	v.lastRefreshed = time.Now()
+	v.mu.Unlock()
+
+	if resp.StatusCode == http.StatusNotModified {
+		v.mu.Lock()
+		v.expiresAt = time.Now().Add(v.ttl(resp))
+		v.mu.Unlock()
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks: fetch %s: unexpected status %d", v.url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("jwks: read response: %w", err)
+	}
+
+	var doc jwkDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("jwks: parse response: %w", err)
+	}
+
+	keys := make(map[string]jwksKey, len(doc.Keys))
+	for _, entry := range doc.Keys {
+		key, err := parseJWK(entry)
+		if err != nil {
+			return fmt.Errorf("jwks: parse key %q: %w", entry.Kid, err)
+		}
+		keys[entry.Kid] = key
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.etag = resp.Header.Get("ETag")
+	v.expiresAt = time.Now().Add(v.ttl(resp))
+	v.mu.Unlock()
+	return nil
+}
+
+// ttl resolves how long the document just fetched in resp should be
+// trusted: an explicit WithJWKSCache override takes precedence, then the
+// response's own Cache-Control max-age, then a one-hour default.
+func (v *jwksVerifier) ttl(resp *http.Response) time.Duration {
+	if v.cacheTTL > 0 {
+		return v.cacheTTL
+	}
+	for _, directive := range strings.Split(resp.Header.Get("Cache-Control"), ",") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(directive), "max-age="); ok {
+			if seconds, err := strconv.Atoi(rest); err == nil && seconds > 0 {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return time.Hour
+}
+
+func parseJWK(entry jwkEntry) (jwksKey, error) {
+	switch entry.Kty {
+	case "EC":
+		if entry.Crv != "P-256" {
+			return jwksKey{}, fmt.Errorf("unsupported EC curve %q", entry.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(entry.X)
+		if err != nil {
+			return jwksKey{}, fmt.Errorf("decode x: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(entry.Y)
+		if err != nil {
+			return jwksKey{}, fmt.Errorf("decode y: %w", err)
+		}
+		return jwksKey{pub: &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}}, nil
+	case "OKP":
+		if entry.Crv != "Ed25519" {
+			return jwksKey{}, fmt.Errorf("unsupported OKP curve %q", entry.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(entry.X)
+		if err != nil {
+			return jwksKey{}, fmt.Errorf("decode x: %w", err)
+		}
+		return jwksKey{pub: ed25519.PublicKey(x)}, nil
+	default:
+		return jwksKey{}, fmt.Errorf("unsupported key type %q", entry.Kty)
+	}
+}
+
+// verify checks sig (raw signature bytes) over signingInput using the
+// JWKS entry for kid.
+func (v *jwksVerifier) verify(ctx context.Context, httpClient *http.Client, kid string, signingInput, sig []byte) error {
+	key, err := v.keyFor(ctx, httpClient, kid)
+	if err != nil {
+		return &KeyVerificationError{Message: err.Error()}
+	}
+
+	switch pub := key.pub.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(signingInput)
+		if !ecdsa.VerifyASN1(pub, digest[:], sig) {
+			return &KeyVerificationError{Message: fmt.Sprintf("signature does not verify for kid %q", kid)}
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(pub, signingInput, sig) {
+			return &KeyVerificationError{Message: fmt.Sprintf("signature does not verify for kid %q", kid)}
+		}
+	default:
+		return &KeyVerificationError{Message: fmt.Sprintf("unsupported key type for kid %q", kid)}
+	}
+	return nil
+}
+
+// verifyKeyResponse checks a PublicKey/AdminPublicKey response body
+// against the client's configured JWKS, if any, returning the verified
+// PEM key on success. With no JWKS configured, body is returned
+// unchanged.
+func (c *Client) verifyKeyResponse(ctx context.Context, httpResp *http.Response, body []byte) (string, error) {
+	if c.jwks == nil {
+		return string(body), nil
+	}
+
+	if header := httpResp.Header.Get("X-Key-Signature"); header != "" {
+		parts := strings.SplitN(header, ".", 3)
+		if len(parts) != 3 {
+			return "", &KeyVerificationError{Message: "malformed X-Key-Signature header"}
+		}
+		kid, sigB64 := parts[0], parts[2]
+		sig, err := base64.RawURLEncoding.DecodeString(sigB64)
+		if err != nil {
+			return "", &KeyVerificationError{Message: "malformed X-Key-Signature signature encoding"}
+		}
+		if err := c.jwks.verify(ctx, c.httpClient, kid, body, sig); err != nil {
+			return "", err
+		}
+		return string(body), nil
+	}
+
+	var envelope struct {
+		Key string `json:"key"`
+		Sig string `json:"sig"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(body, &envelope); err == nil && envelope.Key != "" && envelope.Sig != "" {
+		sig, err := base64.RawURLEncoding.DecodeString(envelope.Sig)
+		if err != nil {
+			return "", &KeyVerificationError{Message: "malformed signature encoding"}
+		}
+		if err := c.jwks.verify(ctx, c.httpClient, envelope.Kid, []byte(envelope.Key), sig); err != nil {
+			return "", err
+		}
+		return envelope.Key, nil
+	}
+
+	return "", &KeyVerificationError{Message: "response was not signed"}
+}
@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testPublicKeyPEM = `-----BEGIN PGP PUBLIC KEY BLOCK-----
+test-key-data
+-----END PGP PUBLIC KEY BLOCK-----`
+
+func jwksDocJSON(kid string, pub *ecdsa.PublicKey) string {
+	x := base64.RawURLEncoding.EncodeToString(pub.X.Bytes())
+	y := base64.RawURLEncoding.EncodeToString(pub.Y.Bytes())
+	return fmt.Sprintf(`{"keys":[{"kty":"EC","crv":"P-256","x":%q,"y":%q,"kid":%q}]}`, x, y, kid)
+}
+
+func signKeyResponse(t *testing.T, priv *ecdsa.PrivateKey, body []byte) []byte {
+	t.Helper()
+	digest := sha256.Sum256(body)
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	if err != nil {
+		t.Fatalf("sign key response: %v", err)
+	}
+	return sig
+}
+
+func TestPublicKeyVerifiesXKeySignatureHeader(t *testing.T) {
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == defaultJWKSPath {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(jwksDocJSON("key-1", &signingKey.PublicKey)))
+			return
+		}
+
+		body := []byte(testPublicKeyPEM)
+		sig := signKeyResponse(t, signingKey, body)
+		w.Header().Set("X-Key-Signature", fmt.Sprintf("key-1.ES256.%s", base64.RawURLEncoding.EncodeToString(sig)))
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithJWKS(""))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	key, err := c.PublicKey(context.Background(), "")
+	if err != nil {
+		t.Fatalf("PublicKey: %v", err)
+	}
+	if key != testPublicKeyPEM {
+		t.Errorf("PublicKey = %q, want %q", key, testPublicKeyPEM)
+	}
+}
+
+func TestPublicKeyVerifiesJSONEnvelope(t *testing.T) {
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == defaultJWKSPath {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(jwksDocJSON("key-1", &signingKey.PublicKey)))
+			return
+		}
+
+		sig := signKeyResponse(t, signingKey, []byte(testPublicKeyPEM))
+		envelope, _ := json.Marshal(struct {
+			Key string `json:"key"`
+			Sig string `json:"sig"`
+			Kid string `json:"kid"`
+		}{
+			Key: testPublicKeyPEM,
+			Sig: base64.RawURLEncoding.EncodeToString(sig),
+			Kid: "key-1",
+		})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(envelope)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithJWKS(""))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	key, err := c.PublicKey(context.Background(), "")
+	if err != nil {
+		t.Fatalf("PublicKey: %v", err)
+	}
+	if key != testPublicKeyPEM {
+		t.Errorf("PublicKey = %q, want %q", key, testPublicKeyPEM)
+	}
+}
+
+func TestPublicKeyRejectsUnsignedResponse(t *testing.T) {
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == defaultJWKSPath {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(jwksDocJSON("key-1", &signingKey.PublicKey)))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(testPublicKeyPEM))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithJWKS(""))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := c.PublicKey(context.Background(), ""); !IsKeyVerificationError(err) {
+		t.Errorf("expected KeyVerificationError for unsigned response, got %v", err)
+	}
+}
+
+func TestPublicKeyRejectsUntrustedSigner(t *testing.T) {
+	trustedKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate trusted key: %v", err)
+	}
+	untrustedKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate untrusted key: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == defaultJWKSPath {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(jwksDocJSON("key-1", &trustedKey.PublicKey)))
+			return
+		}
+
+		body := []byte(testPublicKeyPEM)
+		sig := signKeyResponse(t, untrustedKey, body)
+		w.Header().Set("X-Key-Signature", fmt.Sprintf("key-1.ES256.%s", base64.RawURLEncoding.EncodeToString(sig)))
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithJWKS(""))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := c.PublicKey(context.Background(), ""); !IsKeyVerificationError(err) {
+		t.Errorf("expected KeyVerificationError for untrusted signer, got %v", err)
+	}
+}
+
+func TestPublicKeyRejectsUnknownKid(t *testing.T) {
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == defaultJWKSPath {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(jwksDocJSON("known-kid", &signingKey.PublicKey)))
+			return
+		}
+
+		body := []byte(testPublicKeyPEM)
+		sig := signKeyResponse(t, signingKey, body)
+		w.Header().Set("X-Key-Signature", fmt.Sprintf("unknown-kid.ES256.%s", base64.RawURLEncoding.EncodeToString(sig)))
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithJWKS(""))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := c.PublicKey(context.Background(), ""); !IsKeyVerificationError(err) {
+		t.Errorf("expected KeyVerificationError for unknown kid, got %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Upload is a handle to an in-progress resumable key upload, started by
+// StartKeyUpload for HSM-exported keys or subkey bundles too large to
+// send in UploadKey's single POST body. It is not safe for concurrent
+// use: WriteChunk must be called with chunks in sequence, since each one
+// advances the server's expected offset.
+type Upload struct {
+	client   *Client
+	ID       string
+	location string
+	offset   int64
+}
+
+// UploadKeyResult is returned by Upload.Finish once the server has
+// verified the uploaded key material against the provided digest and
+// stored it.
+type UploadKeyResult struct {
+	KeyID       string `json:"keyId"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+type startUploadResponse struct {
+	ID       string `json:"id"`
+	Location string `json:"location"`
+}
+
+// StartKeyUpload begins a resumable upload for keyID's armored key
+// material. The returned Upload starts at offset 0; write the key bytes
+// to it via WriteChunk or Writer, then call Finish with the SHA-256
+// digest of the complete key to commit it.
+func (c *Client) StartKeyUpload(ctx context.Context, keyID string) (*Upload, error) {
+	if keyID == "" {
+		return nil, &ValidationError{Code: "INVALID_REQUEST", Message: "keyID cannot be empty"}
+	}
+
+	var upload *Upload
+	err := c.retrier.Do(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/admin/keys/"+keyID+"/uploads", nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", c.authHeader())
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			return newUnexpectedStatusError(resp.StatusCode)
+		}
+
+		var body startUploadResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return err
+		}
+
+		location := body.Location
+		if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+			location = c.baseURL + location
+		}
+		upload = &Upload{client: c, ID: body.ID, location: location}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+// Offset reports how many bytes of the upload the server has
+// acknowledged so far: where a resumed upload should continue writing
+// from after a failed WriteChunk.
+func (u *Upload) Offset() int64 {
+	return u.offset
+}
+
+// WriteChunk PATCHes p as the next chunk of the upload, with a
+// Content-Range header describing its position in the overall byte
+// stream, and returns the offset the server now expects the next chunk
+// to start at. A 202 Accepted response is the expected outcome for an
+// intermediate chunk.
+//
+// WriteChunk does not retry: on error, Offset is left unchanged so the
+// caller can resume by calling WriteChunk again with the same chunk (or
+// re-reading it from that offset in the source file).
+func (u *Upload) WriteChunk(ctx context.Context, p []byte) (int64, error) {
+	start := u.offset
+	end := start + int64(len(p)) - 1
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, u.location, bytes.NewReader(p))
+	if err != nil {
+		return u.offset, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if end >= start {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/*", start, end))
+	}
+	req.Header.Set("Authorization", u.client.authHeader())
+
+	resp, err := u.client.httpClient.Do(req)
+	if err != nil {
+		return u.offset, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return u.offset, newUnexpectedStatusError(resp.StatusCode)
+	}
+
+	var body struct {
+		Offset *int64 `json:"offset"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && err != io.EOF {
+		return u.offset, err
+	}
+
+	if body.Offset != nil {
+		u.offset = *body.Offset
+	} else {
+		u.offset = end + 1
+	}
+	return u.offset, nil
+}
+
+// Finish commits the upload with sha256, the hex-encoded SHA-256 digest
+// of the complete key material written via WriteChunk/Writer, and
+// returns the stored key's info once the server has verified it.
+func (u *Upload) Finish(ctx context.Context, sha256 string) (*UploadKeyResult, error) {
+	payload, err := json.Marshal(struct {
+		SHA256 string `json:"sha256"`
+	}{SHA256: sha256})
+	if err != nil {
+		return nil, err
+	}
+
+	var result *UploadKeyResult
+	err = u.client.retrier.Do(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.location, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", u.client.authHeader())
+
+		resp, err := u.client.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+			return newUnexpectedStatusError(resp.StatusCode)
+		}
+
+		var body UploadKeyResult
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return err
+		}
+		result = &body
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Writer adapts Upload to io.Writer, binding it to ctx, so callers can
+// io.Copy a key file directly into the upload; each Write call becomes
+// one WriteChunk PATCH sized to whatever io.Copy's buffer provides.
+func (u *Upload) Writer(ctx context.Context) io.Writer {
+	return &uploadWriter{upload: u, ctx: ctx}
+}
+
+type uploadWriter struct {
+	upload *Upload
+	ctx    context.Context
+}
+
+func (w *uploadWriter) Write(p []byte) (int, error) {
+	if _, err := w.upload.WriteChunk(w.ctx, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
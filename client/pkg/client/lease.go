@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// RenewFunc fetches a fresh bearer token and its remaining TTL. It has
+// the same shape as TokenSource.Token and is provided as a convenience
+// for callers that would rather pass a function than implement an
+// interface.
+type RenewFunc func(ctx context.Context) (token string, ttl time.Duration, err error)
+
+// WithRenewableToken is equivalent to WithTokenRenewer(TokenSourceFunc(fn), opts...)
+// for callers who already have a refresh function (e.g. wrapping an STS
+// or OIDC exchange call) rather than a TokenSource implementation.
+func WithRenewableToken(fn RenewFunc, opts ...RenewerOption) Option {
+	return WithTokenRenewer(TokenSourceFunc(fn), opts...)
+}
+
+// oauth2TokenSource adapts an oauth2.TokenSource to client.TokenSource so
+// it can drive a TokenRenewer. TTL is derived from the token's Expiry;
+// tokens with no expiry are treated as having a long, fixed lease since
+// the renewer still needs a positive TTL to schedule its next refresh.
+type oauth2TokenSource struct {
+	ts oauth2.TokenSource
+}
+
+// noExpiryLease is the TTL assumed for oauth2 tokens that report no
+// Expiry, so the renewer still has something to schedule against.
+const noExpiryLease = time.Hour
+
+func (o oauth2TokenSource) Token(_ context.Context) (string, time.Duration, error) {
+	tok, err := o.ts.Token()
+	if err != nil {
+		return "", 0, err
+	}
+
+	ttl := noExpiryLease
+	if !tok.Expiry.IsZero() {
+		ttl = time.Until(tok.Expiry)
+		if ttl < 0 {
+			ttl = 0
+		}
+	}
+	return tok.AccessToken, ttl, nil
+}
+
+// WithOIDCTokenSource configures the client to obtain and auto-renew its
+// bearer token from an oauth2.TokenSource (e.g. a golang.org/x/oauth2
+// client-credentials or OIDC token source). The token is refreshed in
+// the background before it expires, matching the lease-renewal pattern
+// used by hashicorp/vault's api.Renewer.
+func WithOIDCTokenSource(ts oauth2.TokenSource, opts ...RenewerOption) Option {
+	return WithTokenRenewer(oauth2TokenSource{ts: ts}, opts...)
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+type staticOAuth2Source struct {
+	tok *oauth2.Token
+}
+
+func (s staticOAuth2Source) Token() (*oauth2.Token, error) {
+	return s.tok, nil
+}
+
+func TestOAuth2TokenSourceDerivesTTLFromExpiry(t *testing.T) {
+	expiry := time.Now().Add(10 * time.Minute)
+	src := oauth2TokenSource{ts: staticOAuth2Source{tok: &oauth2.Token{
+		AccessToken: "abc",
+		Expiry:      expiry,
+	}}}
+
+	token, ttl, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if token != "abc" {
+		t.Errorf("expected token 'abc', got %q", token)
+	}
+	if ttl <= 0 || ttl > 10*time.Minute {
+		t.Errorf("expected ttl in (0, 10m], got %v", ttl)
+	}
+}
+
+func TestOAuth2TokenSourceFallsBackToNoExpiryLease(t *testing.T) {
+	src := oauth2TokenSource{ts: staticOAuth2Source{tok: &oauth2.Token{AccessToken: "abc"}}}
+
+	_, ttl, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if ttl != noExpiryLease {
+		t.Errorf("expected fallback ttl %v, got %v", noExpiryLease, ttl)
+	}
+}
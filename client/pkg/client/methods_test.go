@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -537,6 +538,76 @@ func TestAuditFilterWithAllFields(t *testing.T) {
 	}
 }
 
+// TestRetryAfterHonoredOnAllRetryableEndpoints verifies that a 503/500
+// with a Retry-After header populates ServiceError.RetryAfter for every
+// retryable endpoint, not just Sign.
+func TestRetryAfterHonoredOnAllRetryableEndpoints(t *testing.T) {
+	retryAfterServer := func(status int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(status)
+			_ = json.NewEncoder(w).Encode(map[string]string{"code": "INTERNAL_ERROR", "error": "overloaded"})
+		}))
+	}
+
+	requireRetryAfter := func(t *testing.T, err error) {
+		t.Helper()
+		var serviceErr *ServiceError
+		if !errors.As(err, &serviceErr) {
+			t.Fatalf("expected a *ServiceError, got %v", err)
+		}
+		if serviceErr.RetryAfter != 7*time.Second {
+			t.Errorf("RetryAfter = %v, want 7s", serviceErr.RetryAfter)
+		}
+	}
+
+	t.Run("Health", func(t *testing.T) {
+		server := retryAfterServer(503)
+		defer server.Close()
+
+		client, _ := New(server.URL, WithMaxRetries(0))
+		_, err := client.Health(context.Background())
+		requireRetryAfter(t, err)
+	})
+
+	t.Run("ListKeys", func(t *testing.T) {
+		server := retryAfterServer(500)
+		defer server.Close()
+
+		client, _ := New(server.URL, WithMaxRetries(0))
+		_, err := client.ListKeys(context.Background())
+		requireRetryAfter(t, err)
+	})
+
+	t.Run("DeleteKey", func(t *testing.T) {
+		server := retryAfterServer(500)
+		defer server.Close()
+
+		client, _ := New(server.URL, WithMaxRetries(0))
+		err := client.DeleteKey(context.Background(), "key-123")
+		requireRetryAfter(t, err)
+	})
+
+	t.Run("AdminPublicKey", func(t *testing.T) {
+		server := retryAfterServer(500)
+		defer server.Close()
+
+		client, _ := New(server.URL, WithMaxRetries(0))
+		_, err := client.AdminPublicKey(context.Background(), "key-123")
+		requireRetryAfter(t, err)
+	})
+
+	t.Run("AuditLogs", func(t *testing.T) {
+		server := retryAfterServer(500)
+		defer server.Close()
+
+		client, _ := New(server.URL, WithMaxRetries(0))
+		_, err := client.AuditLogs(context.Background(), AuditFilter{})
+		requireRetryAfter(t, err)
+	})
+}
+
 // BenchmarkHealth benchmarks Health() method
 func BenchmarkHealth(b *testing.B) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// recordingMiddleware appends name to order before and after delegating,
+// so tests can assert the composed call order.
+func recordingMiddleware(order *[]string, name string) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			*order = append(*order, name+":before")
+			resp, err := next.RoundTrip(req)
+			*order = append(*order, name+":after")
+			return resp, err
+		})
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithMiddlewareComposesInOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var order []string
+	c, err := New(server.URL, WithMiddleware(
+		recordingMiddleware(&order, "outer"),
+		recordingMiddleware(&order, "inner"),
+	))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	resp, err := c.httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	want := []string{"outer:before", "inner:before", "inner:after", "outer:after"}
+	if len(order) != len(want) {
+		t.Fatalf("expected order %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("expected order %v, got %v", want, order)
+			break
+		}
+	}
+}
+
+func TestWithMiddlewareWrapsDefaultTransportWhenNoCustomTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var called bool
+	c, err := New(server.URL, WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			called = true
+			return next.RoundTrip(req)
+		})
+	}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	resp, err := c.httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if !called {
+		t.Error("expected middleware to be invoked")
+	}
+}
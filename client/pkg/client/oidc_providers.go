@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// oidcRefreshSkew is how far ahead of a token's exp claim the platform
+// OIDC token sources refresh, matching the 60s skew used by most
+// workload-identity integrations (GitHub Actions, GKE, etc.).
+const oidcRefreshSkew = 60 * time.Second
+
+// WithGitHubActionsOIDC configures the client to fetch its bearer token
+// from the GitHub Actions OIDC provider using the standard workload
+// identity flow (ACTIONS_ID_TOKEN_REQUEST_URL / _TOKEN env vars). The
+// token is cached per audience and refreshed when its exp claim comes
+// within oidcRefreshSkew.
+func WithGitHubActionsOIDC(audience string) Option {
+	return WithTokenRenewer(&githubActionsTokenSource{audience: audience, client: http.DefaultClient})
+}
+
+type githubActionsTokenSource struct {
+	audience string
+	client   *http.Client
+}
+
+func (g *githubActionsTokenSource) Token(ctx context.Context) (string, time.Duration, error) {
+	reqURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	reqToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+	if reqURL == "" || reqToken == "" {
+		return "", 0, fmt.Errorf("client: not running in GitHub Actions (ACTIONS_ID_TOKEN_REQUEST_URL/_TOKEN unset)")
+	}
+
+	u, err := url.Parse(reqURL)
+	if err != nil {
+		return "", 0, fmt.Errorf("client: invalid ACTIONS_ID_TOKEN_REQUEST_URL: %w", err)
+	}
+	if g.audience != "" {
+		q := u.Query()
+		q.Set("audience", g.audience)
+		u.RawQuery = q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+reqToken)
+
+	var body struct {
+		Value string `json:"value"`
+	}
+	if err := doJSON(g.client, req, &body); err != nil {
+		return "", 0, fmt.Errorf("client: fetch GitHub Actions OIDC token: %w", err)
+	}
+
+	return tokenWithSkewTTL(body.Value)
+}
+
+// WithGoogleCloudOIDC configures the client to fetch its bearer token
+// from the GCE/GKE metadata server's identity endpoint, as used by GKE
+// Workload Identity. The token is refreshed when its exp claim comes
+// within oidcRefreshSkew.
+func WithGoogleCloudOIDC(audience string) Option {
+	return WithTokenRenewer(&gceMetadataTokenSource{audience: audience, client: http.DefaultClient})
+}
+
+type gceMetadataTokenSource struct {
+	audience string
+	client   *http.Client
+}
+
+const gceMetadataIdentityURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/identity"
+
+func (g *gceMetadataTokenSource) Token(ctx context.Context) (string, time.Duration, error) {
+	u, err := url.Parse(gceMetadataIdentityURL)
+	if err != nil {
+		return "", 0, err
+	}
+	q := u.Query()
+	q.Set("audience", g.audience)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("client: fetch GCE metadata OIDC token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("client: GCE metadata server returned %d", resp.StatusCode)
+	}
+
+	token, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return tokenWithSkewTTL(strings.TrimSpace(string(token)))
+}
+
+// WithGenericOIDCFile configures the client to read its bearer token
+// from a projected service-account token file (the generic Kubernetes
+// pattern used when neither the GitHub Actions nor GKE-specific
+// integrations apply). The file is re-read whenever its mtime changes.
+func WithGenericOIDCFile(path string) Option {
+	return WithTokenRenewer(FileToken(path))
+}
+
+type fileTokenSource struct {
+	path string
+
+	mu     sync.Mutex
+	mtime  time.Time
+	cached string
+}
+
+func (f *fileTokenSource) Token(_ context.Context) (string, time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", 0, fmt.Errorf("client: stat OIDC token file: %w", err)
+	}
+
+	if info.ModTime().Equal(f.mtime) && f.cached != "" {
+		return tokenWithSkewTTL(f.cached)
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", 0, fmt.Errorf("client: read OIDC token file: %w", err)
+	}
+
+	f.mtime = info.ModTime()
+	f.cached = strings.TrimSpace(string(data))
+	return tokenWithSkewTTL(f.cached)
+}
+
+// tokenWithSkewTTL decodes a JWT's exp claim (without verifying its
+// signature, since it is only used to schedule client-side refresh) and
+// returns a TTL that leaves oidcRefreshSkew of headroom before expiry.
+func tokenWithSkewTTL(token string) (string, time.Duration, error) {
+	exp, err := jwtExpiry(token)
+	if err != nil {
+		return "", 0, err
+	}
+
+	ttl := time.Until(exp) - oidcRefreshSkew
+	if ttl < 0 {
+		ttl = 0
+	}
+	return token, ttl, nil
+}
+
+func jwtExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("client: malformed JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("client: decode JWT payload: %w", err)
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("client: decode JWT claims: %w", err)
+	}
+
+	return time.Unix(claims.Exp, 0), nil
+}
+
+func doJSON(client *http.Client, req *http.Request, out any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func makeTestJWT(t *testing.T, exp int64) string {
+	t.Helper()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload, err := json.Marshal(map[string]int64{"exp": exp})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	return header + "." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+func TestJWTExpiry(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	token := makeTestJWT(t, exp)
+
+	got, err := jwtExpiry(token)
+	if err != nil {
+		t.Fatalf("jwtExpiry: %v", err)
+	}
+	if got.Unix() != exp {
+		t.Errorf("expected exp %d, got %d", exp, got.Unix())
+	}
+}
+
+func TestJWTExpiryRejectsMalformedToken(t *testing.T) {
+	if _, err := jwtExpiry("not-a-jwt"); err == nil {
+		t.Error("expected error for malformed JWT")
+	}
+}
+
+func TestTokenWithSkewTTLAppliesSkew(t *testing.T) {
+	exp := time.Now().Add(5 * time.Minute)
+	token := makeTestJWT(t, exp.Unix())
+
+	_, ttl, err := tokenWithSkewTTL(token)
+	if err != nil {
+		t.Fatalf("tokenWithSkewTTL: %v", err)
+	}
+
+	want := time.Until(exp) - oidcRefreshSkew
+	if diff := ttl - want; diff < -time.Second || diff > time.Second {
+		t.Errorf("expected ttl near %v, got %v", want, ttl)
+	}
+}
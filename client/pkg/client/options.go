@@ -1,17 +1,55 @@
 package client
 
 import (
+	"crypto"
+	"crypto/tls"
+	"net/http"
 	"time"
 )
 
 // Options configures the client behavior.
 type Options struct {
-	timeout          time.Duration
-	authToken        string
-	maxRetries       int
-	retryWaitMin     time.Duration
-	retryWaitMax     time.Duration
-	retryOnRateLimit bool
+	timeout           time.Duration
+	authToken         string
+	maxRetries        int
+	retryWaitMin      time.Duration
+	retryWaitMax      time.Duration
+	retryOnRateLimit  bool
+	tokenRenewer      *TokenRenewer
+	maxBatchSize      int
+	breaker           *circuitBreaker
+	keyCache          *keyCache
+	requestSigningKey crypto.Signer
+	requestSigningKID string
+	jwksURL           *string
+	jwksCacheTTL      time.Duration
+	authenticator     Authenticator
+	idempotencyCache  *idempotencyCache
+	authChallenge     bool
+
+	unixSocketPath string
+	tlsConfig      *tls.Config
+	tlsCertFile    string
+	tlsKeyFile     string
+	tlsCAFile      string
+
+	useBatchTransport bool
+	batchSize         int
+	batchLinger       time.Duration
+
+	retryPolicy      RetryPolicy
+	retryHook        RetryHook
+	backoff          Backoff
+	retryBackoffFunc RetryBackoffFunc
+	onRetry          OnRetryFunc
+	onGiveUp         OnGiveUpFunc
+	rateLimitBudget  *RateLimitPolicy
+
+	idempotencyKeyFunc func() string
+
+	middleware []func(http.RoundTripper) http.RoundTripper
+
+	requestBodyBuffering bool
 }
 
 func defaultOptions() *Options {
@@ -73,3 +111,184 @@ func WithoutRateLimitRetry() Option {
 		o.retryOnRateLimit = false
 	}
 }
+
+// WithTokenRenewer replaces the client's static bearer token with a
+// TokenRenewer backed by source. The renewer is started when the Client
+// is constructed and stopped by Client.Close; it can also be stopped
+// directly via Client.TokenRenewer().Stop.
+func WithTokenRenewer(source TokenSource, opts ...RenewerOption) Option {
+	return func(o *Options) {
+		o.tokenRenewer = NewTokenRenewer(source, opts...)
+	}
+}
+
+// WithRetryPolicy overrides the Retrier's built-in retry rules with a
+// custom policy, e.g. to never retry non-idempotent operations or to
+// retry on additional error conditions.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *Options) {
+		o.retryPolicy = policy
+	}
+}
+
+// RetryConfig bundles the retry knobs most callers want to set together
+// into a single option, rather than combining WithMaxRetries,
+// WithRetryWait, WithBackoff, and WithRetryPolicy individually.
+//
+// MaxAttempts is the total number of attempts, including the first
+// (i.e. MaxAttempts-1 retries); BaseDelay, MaxDelay, and Jitter
+// parameterize a jittered exponential backoff between attempts; and
+// Retryable, if set, narrows which errors are retried beyond the
+// Retrier's built-in classification of ServiceError/RateLimitError.
+// Retryable sees the error this package already classifies responses
+// into (ServiceError, RateLimitError, ValidationError, AuthError, or a
+// raw transport error) rather than the underlying *http.Response,
+// since by the time the Retrier runs, the response has already been
+// decoded into one of those types.
+//
+// Regardless of Retryable, a ctx that ends while the Retrier is waiting
+// between attempts stops the loop: the Retrier returns a
+// *RetryAbortedError whose Unwrap chain reaches ctx.Err(), so
+// errors.Is(err, context.Canceled) and the like still hold.
+type RetryConfig struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      float64
+	Retryable   func(err error) bool
+}
+
+// WithRetry configures the client's retry behavior from a RetryConfig.
+// It is equivalent to calling WithMaxRetries, WithBackoff, and (if
+// Retryable is set) WithRetryPolicy together, for callers who prefer
+// one bundled option over several fine-grained ones.
+func WithRetry(cfg RetryConfig) Option {
+	return func(o *Options) {
+		if cfg.MaxAttempts > 0 {
+			o.maxRetries = cfg.MaxAttempts - 1
+		}
+		o.backoff = jitteredBackoff{Base: cfg.BaseDelay, Max: cfg.MaxDelay, Jitter: cfg.Jitter}
+		if cfg.Retryable != nil {
+			retryable := cfg.Retryable
+			o.retryPolicy = func(err error, _ int) (bool, time.Duration) {
+				return retryable(err), 0
+			}
+		}
+	}
+}
+
+// WithRetryHook registers a callback invoked after every failed attempt
+// with the retry decision that was made, for logging or metrics.
+func WithRetryHook(hook RetryHook) Option {
+	return func(o *Options) {
+		o.retryHook = hook
+	}
+}
+
+// WithOnRetry registers a callback invoked once per retry, just before
+// the Retrier sleeps for the next attempt. Use it to emit metrics,
+// structured logs, or OpenTelemetry spans per attempt without wrapping
+// every Sign/UploadKey/AuditLogs call.
+func WithOnRetry(fn OnRetryFunc) Option {
+	return func(o *Options) {
+		o.onRetry = fn
+	}
+}
+
+// WithOnGiveUp registers a callback invoked exactly once, when the
+// Retrier's loop exits with a non-nil error and will not retry again.
+func WithOnGiveUp(fn OnGiveUpFunc) Option {
+	return func(o *Options) {
+		o.onGiveUp = fn
+	}
+}
+
+// WithRateLimitPolicy gives RateLimitError its own retry budget,
+// independent of maxRetries: the Retrier keeps retrying a 429 as long as
+// cumulative Retry-After waits stay under maxWait and the number of
+// rate-limit retries stays under maxAttempts, even after the general
+// maxRetries counter would otherwise have given up. Failures other than
+// RateLimitError are unaffected and keep using maxRetries.
+func WithRateLimitPolicy(maxWait time.Duration, maxAttempts int) Option {
+	return func(o *Options) {
+		o.rateLimitBudget = &RateLimitPolicy{MaxWait: maxWait, MaxAttempts: maxAttempts}
+	}
+}
+
+// WithBackoff overrides the Retrier's built-in exponential-backoff
+// schedule with a custom Backoff strategy, e.g. ConstantBackoff,
+// ExponentialBackoff, or DecorrelatedJitterBackoff.
+func WithBackoff(backoff Backoff) Option {
+	return func(o *Options) {
+		o.backoff = backoff
+	}
+}
+
+// RetryBackoffFunc computes the wait before the next retry attempt.
+// attempt is 1-based, counting the retry about to be made (the first
+// retry is attempt 1). resp is a synthetic *http.Response carrying only
+// StatusCode, reconstructed from the last error's ServiceError (5xx) or
+// RateLimitError (429) classification -- the real response body is
+// already drained and discarded by the time the Retrier runs, so no
+// header or body access is possible. resp is nil when the failure was
+// an AuthError, a ValidationError, or a raw transport-level error, none
+// of which carry a status code. A returned duration <= 0 aborts
+// retrying and surfaces err to the caller immediately, instead of
+// retrying with no wait.
+type RetryBackoffFunc func(attempt int, resp *http.Response, err error) time.Duration
+
+// WithRetryBackoff replaces the Retrier's backoff schedule entirely with
+// fn, overriding both the built-in exponential-with-jitter default and
+// any Backoff set via WithBackoff. Unlike WithBackoff, fn also sees the
+// error that triggered the retry, and can abort retrying outright by
+// returning a non-positive duration.
+func WithRetryBackoff(fn RetryBackoffFunc) Option {
+	return func(o *Options) {
+		o.retryBackoffFunc = fn
+	}
+}
+
+// WithIdempotencyKeyFunc sets a function called once per POST request to
+// produce an Idempotency-Key header value. Configuring this allows
+// otherwise non-idempotent requests (e.g. Sign) to be safely retried
+// after a transport-level failure, since the server can deduplicate a
+// retried request by its key.
+func WithIdempotencyKeyFunc(fn func() string) Option {
+	return func(o *Options) {
+		o.idempotencyKeyFunc = fn
+	}
+}
+
+// WithMiddleware wraps the client's HTTP transport with the given
+// middleware, mirroring the standard func(http.Handler) http.Handler
+// pattern used by Go HTTP server stacks, applied instead to the client's
+// outgoing http.RoundTripper. Middleware is applied in the order given:
+// the first middleware sees a request first and its response last, i.e.
+// WithMiddleware(a, b) wraps the transport as a(b(transport)). See the
+// client/middleware subpackage for ready-made middleware (RequestID,
+// AppInfo, Logging, Metrics, OpenTelemetryTracing).
+func WithMiddleware(mw ...func(http.RoundTripper) http.RoundTripper) Option {
+	return func(o *Options) {
+		o.middleware = append(o.middleware, mw...)
+	}
+}
+
+// WithRequestBodyBuffering allows SignStream to retry on transient
+// failures by buffering the entire streamed payload in memory so it can
+// be replayed. Without this option, a non-seekable reader can only be
+// sent once: SignStream skips retry entirely rather than risk sending a
+// partially-consumed body.
+func WithRequestBodyBuffering() Option {
+	return func(o *Options) {
+		o.requestBodyBuffering = true
+	}
+}
+
+// WithMaxBatchSize sets the maximum number of items SignBatch sends in a
+// single HTTP request. Larger inputs are automatically split into
+// multiple sequential requests. The default is 100.
+func WithMaxBatchSize(n int) Option {
+	return func(o *Options) {
+		o.maxBatchSize = n
+	}
+}
@@ -1,6 +1,8 @@
 package client
 
 import (
+	"context"
+	"net/http"
 	"testing"
 	"time"
 )
@@ -236,6 +238,20 @@ func TestRetryWaitWithZeroDuration(t *testing.T) {
 	}
 }
 
+// TestWithMiddlewareAppendsAcrossCalls tests that repeated WithMiddleware
+// calls accumulate rather than overwrite.
+func TestWithMiddlewareAppendsAcrossCalls(t *testing.T) {
+	opts := defaultOptions()
+	noop := func(next http.RoundTripper) http.RoundTripper { return next }
+
+	WithMiddleware(noop)(opts)
+	WithMiddleware(noop, noop)(opts)
+
+	if len(opts.middleware) != 3 {
+		t.Errorf("expected 3 accumulated middleware, got %d", len(opts.middleware))
+	}
+}
+
 // BenchmarkOptionApplication benchmarks applying options
 func BenchmarkOptionApplication(b *testing.B) {
 	opts := []Option{
@@ -253,6 +269,29 @@ func BenchmarkOptionApplication(b *testing.B) {
 	}
 }
 
+// BenchmarkOptionApplicationWithHooks is like BenchmarkOptionApplication
+// but also applies WithOnRetry/WithOnGiveUp, to guard against the hook
+// path adding per-attempt allocations beyond storing the func values.
+func BenchmarkOptionApplicationWithHooks(b *testing.B) {
+	onRetry := func(context.Context, int, error, time.Duration) {}
+	onGiveUp := func(int, error) {}
+	opts := []Option{
+		WithTimeout(30 * time.Second),
+		WithMaxRetries(3),
+		WithRetryWait(1*time.Second, 30*time.Second),
+		WithOIDCToken("test-token"),
+		WithOnRetry(onRetry),
+		WithOnGiveUp(onGiveUp),
+	}
+
+	for b.Loop() {
+		options := defaultOptions()
+		for _, opt := range opts {
+			opt(options)
+		}
+	}
+}
+
 // BenchmarkDefaultOptions benchmarks creating default options
 func BenchmarkDefaultOptions(b *testing.B) {
 	for b.Loop() {
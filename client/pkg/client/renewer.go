@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TokenSource supplies bearer tokens to a TokenRenewer. Implementations
+// return the token to use along with its remaining time-to-live; the
+// renewer schedules the next refresh relative to the returned TTL.
+//
+// Token reports a TTL (time.Duration) rather than an absolute expiry
+// (time.Time) deliberately: nextRefresh's grace-fraction/jitter
+// scheduling is computed as a fraction of the remaining lifetime, which
+// is what a TTL gives directly. An expiry would just be subtracted from
+// time.Now() at the call site to get the same duration, so there's no
+// information lost by requiring the duration up front instead.
+type TokenSource interface {
+	Token(ctx context.Context) (token string, ttl time.Duration, err error)
+}
+
+// TokenSourceFunc adapts a function to the TokenSource interface.
+type TokenSourceFunc func(ctx context.Context) (string, time.Duration, error)
+
+// Token calls f(ctx).
+func (f TokenSourceFunc) Token(ctx context.Context) (string, time.Duration, error) {
+	return f(ctx)
+}
+
+// RenewerOption configures a TokenRenewer.
+type RenewerOption func(*renewerOptions)
+
+type renewerOptions struct {
+	graceFraction float64
+	jitter        time.Duration
+	retrier       *Retrier
+}
+
+func defaultRenewerOptions() *renewerOptions {
+	return &renewerOptions{
+		graceFraction: 2.0 / 3.0,
+		jitter:        5 * time.Second,
+		retrier:       newRetrier(defaultOptions()),
+	}
+}
+
+// WithRenewGraceFraction sets the fraction of the token TTL at which the
+// renewer schedules its next refresh. The default is 2/3, matching the
+// Vault API renewer's default renew-before-expiry behavior.
+func WithRenewGraceFraction(fraction float64) RenewerOption {
+	return func(o *renewerOptions) {
+		o.graceFraction = fraction
+	}
+}
+
+// WithRenewJitter sets the maximum random jitter subtracted from the
+// scheduled refresh time, to avoid thundering-herd refreshes across
+// many client instances.
+func WithRenewJitter(d time.Duration) RenewerOption {
+	return func(o *renewerOptions) {
+		o.jitter = d
+	}
+}
+
+// TokenRenewer owns a bearer token obtained from a TokenSource and keeps
+// it fresh in a background goroutine, mirroring the renewal loop used by
+// the Vault API's Renewer. It is intended for long-lived processes (CI
+// daemons, git hooks run in a loop) that cannot tolerate a credential
+// expiring mid-operation.
+type TokenRenewer struct {
+	source  TokenSource
+	opts    *renewerOptions
+	header  atomic.Pointer[string]
+	renewCh chan string
+	doneCh  chan error
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewTokenRenewer creates a TokenRenewer backed by source. Call Start to
+// begin the background refresh loop.
+func NewTokenRenewer(source TokenSource, opts ...RenewerOption) *TokenRenewer {
+	o := defaultRenewerOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	r := &TokenRenewer{
+		source:  source,
+		opts:    o,
+		renewCh: make(chan string, 1),
+		doneCh:  make(chan error, 1),
+	}
+	empty := ""
+	r.header.Store(&empty)
+	return r
+}
+
+// Start begins the background renewal loop. It performs an initial
+// synchronous fetch so the renewer has a valid token before returning.
+func (r *TokenRenewer) Start(ctx context.Context) error {
+	token, ttl, err := r.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	r.setToken(token)
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	r.mu.Lock()
+	r.cancel = cancel
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go r.run(runCtx, ttl)
+	return nil
+}
+
+// Stop halts the background renewal loop. It is safe to call multiple
+// times and safe to call without a prior Start.
+func (r *TokenRenewer) Stop() {
+	r.mu.Lock()
+	cancel := r.cancel
+	r.cancel = nil
+	r.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	r.wg.Wait()
+}
+
+// Header returns the current "Bearer <token>" value for use on outgoing
+// requests. It is safe to call concurrently with a refresh in flight.
+func (r *TokenRenewer) Header() string {
+	return *r.header.Load()
+}
+
+// RenewCh returns a channel that receives the new token each time a
+// refresh succeeds. The channel is not closed when the renewer stops.
+func (r *TokenRenewer) RenewCh() <-chan string {
+	return r.renewCh
+}
+
+// DoneCh returns a channel that receives a terminal error if the
+// renewer gives up refreshing (e.g. the TokenSource repeatedly fails).
+// Callers can use this to trigger re-authentication.
+func (r *TokenRenewer) DoneCh() <-chan error {
+	return r.doneCh
+}
+
+func (r *TokenRenewer) run(ctx context.Context, ttl time.Duration) {
+	defer r.wg.Done()
+
+	wait := r.nextRefresh(ttl)
+	for {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		var token string
+		var newTTL time.Duration
+		err := r.opts.retrier.Do(ctx, func() error {
+			var fetchErr error
+			token, newTTL, fetchErr = r.fetch(ctx)
+			return fetchErr
+		})
+		if err != nil {
+			select {
+			case r.doneCh <- err:
+			default:
+			}
+			return
+		}
+
+		r.setToken(token)
+		select {
+		case r.renewCh <- token:
+		default:
+		}
+
+		wait = r.nextRefresh(newTTL)
+	}
+}
+
+func (r *TokenRenewer) fetch(ctx context.Context) (string, time.Duration, error) {
+	return r.source.Token(ctx)
+}
+
+func (r *TokenRenewer) setToken(token string) {
+	header := "Bearer " + token
+	r.header.Store(&header)
+}
+
+func (r *TokenRenewer) nextRefresh(ttl time.Duration) time.Duration {
+	wait := time.Duration(float64(ttl) * r.opts.graceFraction)
+	if r.opts.jitter > 0 {
+		wait -= time.Duration(rand.Int64N(int64(r.opts.jitter)))
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenRenewerStartAndStop(t *testing.T) {
+	calls := 0
+	source := TokenSourceFunc(func(_ context.Context) (string, time.Duration, error) {
+		calls++
+		return "tok", time.Hour, nil
+	})
+
+	r := NewTokenRenewer(source)
+	if err := r.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer r.Stop()
+
+	if calls != 1 {
+		t.Errorf("expected 1 initial fetch, got %d", calls)
+	}
+	if r.Header() != "Bearer tok" {
+		t.Errorf("expected header 'Bearer tok', got %q", r.Header())
+	}
+}
+
+func TestTokenRenewerStartPropagatesSourceError(t *testing.T) {
+	wantErr := errors.New("source unavailable")
+	source := TokenSourceFunc(func(_ context.Context) (string, time.Duration, error) {
+		return "", 0, wantErr
+	})
+
+	r := NewTokenRenewer(source)
+	if err := r.Start(context.Background()); !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}
+
+func TestTokenRenewerNextRefreshAppliesGraceFraction(t *testing.T) {
+	r := NewTokenRenewer(nil, WithRenewGraceFraction(0.5), WithRenewJitter(0))
+
+	wait := r.nextRefresh(10 * time.Second)
+	if wait != 5*time.Second {
+		t.Errorf("expected 5s wait, got %v", wait)
+	}
+}
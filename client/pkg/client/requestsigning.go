@@ -0,0 +1,354 @@
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// WithRequestSigningKey has the client wrap the JSON body of Sign,
+// UploadKey, and DeleteKey in a compact JWS with protected headers
+// {alg, kid, url, nonce}, mirroring the request-signing pattern used by
+// golang.org/x/crypto/acme so the server can detect tampering and reject
+// a request swapped onto a different URL. priv must be an
+// *ecdsa.PrivateKey (alg ES256) or ed25519.PrivateKey (alg EdDSA); any
+// other type is rejected when the client is constructed.
+func WithRequestSigningKey(priv crypto.Signer) Option {
+	return func(o *Options) {
+		o.requestSigningKey = priv
+	}
+}
+
+// WithJWSKey is an alternative to WithRequestSigningKey for deployments
+// that assign key IDs out of band (e.g. a kid already registered with
+// the server) rather than having the client derive one from the public
+// key. priv must be an *ecdsa.PrivateKey (alg ES256), ed25519.PrivateKey
+// (alg EdDSA), or *rsa.PrivateKey (alg RS256); any other type is
+// rejected when the client is constructed.
+func WithJWSKey(priv crypto.Signer, kid string) Option {
+	return func(o *Options) {
+		o.requestSigningKey = priv
+		o.requestSigningKID = kid
+	}
+}
+
+// requestSigner holds the key used to sign outgoing request bodies, plus
+// the alg/kid derived from it once so every signed request doesn't
+// recompute them.
+type requestSigner struct {
+	priv crypto.Signer
+	alg  string
+	kid  string
+}
+
+// newRequestSigner derives the alg for priv and, if kid is empty,
+// computes one from priv's public key; kid is non-empty only when the
+// caller configured it explicitly via WithJWSKey.
+func newRequestSigner(priv crypto.Signer, kid string) (*requestSigner, error) {
+	var alg string
+	switch priv.(type) {
+	case *ecdsa.PrivateKey:
+		alg = "ES256"
+	case ed25519.PrivateKey:
+		alg = "EdDSA"
+	case *rsa.PrivateKey:
+		alg = "RS256"
+	default:
+		return nil, fmt.Errorf("unsupported request signing key type %T", priv)
+	}
+
+	if kid == "" {
+		pubBytes, err := x509.MarshalPKIXPublicKey(priv.Public())
+		if err != nil {
+			return nil, fmt.Errorf("marshal public key: %w", err)
+		}
+		sum := sha256.Sum256(pubBytes)
+		kid = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+
+	return &requestSigner{priv: priv, alg: alg, kid: kid}, nil
+}
+
+func (s *requestSigner) sign(signingInput string) ([]byte, error) {
+	switch priv := s.priv.(type) {
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signingInput))
+		return ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	case ed25519.PrivateKey:
+		return ed25519.Sign(priv, []byte(signingInput)), nil
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signingInput))
+		return rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, digest[:])
+	default:
+		return nil, fmt.Errorf("unsupported request signing key type %T", s.priv)
+	}
+}
+
+// jwsProtectedHeader is the JOSE protected header used by signed
+// requests. URL binds the signature to the exact request URL to defeat
+// request-swapping; Nonce defeats replay.
+type jwsProtectedHeader struct {
+	Alg   string `json:"alg"`
+	Kid   string `json:"kid"`
+	URL   string `json:"url"`
+	Nonce string `json:"nonce"`
+}
+
+// fetchNonce issues a HEAD request and returns the Replay-Nonce header it
+// reports, bootstrapping the client's nonce before the first signed
+// request.
+func (c *Client) fetchNonce(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.baseURL+"/nonce", nil)
+	if err != nil {
+		return "", err
+	}
+	if h := c.authHeader(); h != "" {
+		req.Header.Set("Authorization", h)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetch nonce: %w", err)
+	}
+	defer resp.Body.Close()
+
+	nonce := resp.Header.Get("Replay-Nonce")
+	if nonce == "" {
+		return "", fmt.Errorf("fetch nonce: server did not return a Replay-Nonce header")
+	}
+	return nonce, nil
+}
+
+func (c *Client) currentNonce(ctx context.Context) (string, error) {
+	c.nonceMu.Lock()
+	nonce := c.nonce
+	c.nonceMu.Unlock()
+	if nonce != "" {
+		return nonce, nil
+	}
+	return c.fetchNonce(ctx)
+}
+
+func (c *Client) storeNonce(resp *http.Response) {
+	nonce := resp.Header.Get("Replay-Nonce")
+	if nonce == "" {
+		return
+	}
+	c.nonceMu.Lock()
+	c.nonce = nonce
+	c.nonceMu.Unlock()
+}
+
+func (c *Client) clearNonce() {
+	c.nonceMu.Lock()
+	c.nonce = ""
+	c.nonceMu.Unlock()
+}
+
+// signJWS wraps payload in a compact JWS (flattened JSON serialization)
+// bound to url, fetching a fresh nonce first if the client doesn't
+// already have one cached from a prior response.
+func (c *Client) signJWS(ctx context.Context, url string, payload []byte) (string, error) {
+	nonce, err := c.currentNonce(ctx)
+	if err != nil {
+		return "", err
+	}
+	return c.signJWSWithNonce(url, payload, nonce)
+}
+
+func (c *Client) signJWSWithNonce(url string, payload []byte, nonce string) (string, error) {
+	headerJSON, err := json.Marshal(jwsProtectedHeader{
+		Alg:   c.reqSigner.alg,
+		Kid:   c.reqSigner.kid,
+		URL:   url,
+		Nonce: nonce,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	protected := base64.RawURLEncoding.EncodeToString(headerJSON)
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+
+	sig, err := c.reqSigner.sign(protected + "." + encodedPayload)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(struct {
+		Protected string `json:"protected"`
+		Payload   string `json:"payload"`
+		Signature string `json:"signature"`
+	}{protected, encodedPayload, base64.RawURLEncoding.EncodeToString(sig)})
+	if err != nil {
+		return "", err
+	}
+
+	return string(body), nil
+}
+
+// BadNonceError is returned when the server rejects a signed request's
+// nonce even after the client's one automatic retry with a freshly
+// fetched nonce.
+type BadNonceError struct {
+	Message string
+}
+
+func (e *BadNonceError) Error() string {
+	return fmt.Sprintf("bad nonce: %s", e.Message)
+}
+
+// SignatureRejectedError is returned when the server rejects a signed
+// request's JWS signature, e.g. because the kid is unrecognized or the
+// signature doesn't verify.
+type SignatureRejectedError struct {
+	Message string
+}
+
+func (e *SignatureRejectedError) Error() string {
+	return fmt.Sprintf("signature rejected: %s", e.Message)
+}
+
+// IsBadNonceError returns true if err indicates the server rejected a
+// signed request's nonce.
+func IsBadNonceError(err error) bool {
+	var be *BadNonceError
+	return errors.As(err, &be)
+}
+
+// IsSignatureRejectedError returns true if err indicates the server
+// rejected a signed request's JWS signature.
+func IsSignatureRejectedError(err error) bool {
+	var se *SignatureRejectedError
+	return errors.As(err, &se)
+}
+
+// doSigned sends a JWS-wrapped payload to url via method, decoding a 2xx
+// JSON response into out (when out is non-nil). On a 400 response with
+// error code BAD_NONCE, it refreshes the nonce and retries exactly once.
+// idempotencyKey, when non-empty, is sent as Idempotency-Key on every
+// attempt so a BAD_NONCE retry is still recognized by the server as the
+// same logical request.
+func (c *Client) doSigned(ctx context.Context, method, url string, payload []byte, out any, idempotencyKey string) error {
+	var lastBadNonce *BadNonceError
+
+	for attempt := 0; attempt < 2; attempt++ {
+		body, err := c.signJWS(ctx, url, payload)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/jose+json")
+		if h := c.authHeader(); h != "" {
+			req.Header.Set("Authorization", h)
+		}
+		if idempotencyKey != "" {
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("signed request: %w", err)
+		}
+		c.storeNonce(resp)
+
+		if resp.StatusCode == http.StatusBadRequest {
+			var errBody struct {
+				Code  string `json:"code"`
+				Error string `json:"error"`
+			}
+			data, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			_ = json.Unmarshal(data, &errBody)
+
+			switch errBody.Code {
+			case "BAD_NONCE":
+				lastBadNonce = &BadNonceError{Message: errBody.Error}
+				c.clearNonce()
+				continue
+			case "SIGNATURE_REJECTED":
+				return &SignatureRejectedError{Message: errBody.Error}
+			default:
+				return &ValidationError{Code: errBody.Code, Message: errBody.Error}
+			}
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return newUnexpectedStatusError(resp.StatusCode)
+		}
+		if out == nil {
+			return nil
+		}
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+
+	return lastBadNonce
+}
+
+func (c *Client) signSigned(ctx context.Context, commitData, keyID, idempotencyKey string) (*SignResult, error) {
+	payload, err := json.Marshal(struct {
+		CommitData string `json:"commitData"`
+		KeyID      string `json:"keyId,omitempty"`
+	}{CommitData: commitData, KeyID: keyID})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Signature          string `json:"signature"`
+		RateLimitRemaining *int   `json:"rateLimitRemaining,omitempty"`
+	}
+	if err := c.doSigned(ctx, http.MethodPost, c.baseURL+"/sign", payload, &result, idempotencyKey); err != nil {
+		return nil, err
+	}
+
+	return &SignResult{Signature: result.Signature, RateLimitRemaining: result.RateLimitRemaining}, nil
+}
+
+func (c *Client) uploadKeySigned(ctx context.Context, keyID, armoredPrivateKey string) (*KeyInfo, error) {
+	payload, err := json.Marshal(struct {
+		KeyID             string `json:"keyId"`
+		ArmoredPrivateKey string `json:"armoredPrivateKey"`
+	}{KeyID: keyID, ArmoredPrivateKey: armoredPrivateKey})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		KeyID       string `json:"keyId"`
+		Fingerprint string `json:"fingerprint"`
+	}
+	if err := c.doSigned(ctx, http.MethodPost, c.baseURL+"/admin/keys", payload, &result, ""); err != nil {
+		return nil, err
+	}
+
+	return &KeyInfo{KeyID: result.KeyID, Fingerprint: result.Fingerprint}, nil
+}
+
+func (c *Client) deleteKeySigned(ctx context.Context, keyID string) error {
+	payload, err := json.Marshal(struct {
+		KeyID string `json:"keyId"`
+	}{KeyID: keyID})
+	if err != nil {
+		return err
+	}
+
+	return c.doSigned(ctx, http.MethodDelete, c.baseURL+"/admin/keys/"+keyID, payload, nil, "")
+}
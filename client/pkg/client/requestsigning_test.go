@@ -0,0 +1,326 @@
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeSigner is a crypto.Signer of a type newRequestSigner never
+// recognizes, used to exercise its unsupported-key-type error path.
+type fakeSigner struct{}
+
+func (fakeSigner) Public() crypto.PublicKey { return nil }
+func (fakeSigner) Sign(io.Reader, []byte, crypto.SignerOpts) ([]byte, error) {
+	return nil, nil
+}
+
+func TestNewRequestSignerRejectsUnsupportedKeyType(t *testing.T) {
+	if _, err := newRequestSigner(fakeSigner{}, ""); err == nil {
+		t.Error("expected error for unsupported key type")
+	}
+}
+
+func TestNewRequestSignerAcceptsRSAKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+
+	signer, err := newRequestSigner(rsaKey, "")
+	if err != nil {
+		t.Fatalf("newRequestSigner: %v", err)
+	}
+	if signer.alg != "RS256" {
+		t.Errorf("alg = %q, want RS256", signer.alg)
+	}
+}
+
+func TestNewRequestSignerUsesExplicitKID(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	signer, err := newRequestSigner(priv, "my-explicit-kid")
+	if err != nil {
+		t.Fatalf("newRequestSigner: %v", err)
+	}
+	if signer.kid != "my-explicit-kid" {
+		t.Errorf("kid = %q, want %q", signer.kid, "my-explicit-kid")
+	}
+}
+
+func TestSignRoutesThroughJWSWhenConfigured(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	var sawNonce string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead && r.URL.Path == "/nonce":
+			w.Header().Set("Replay-Nonce", "nonce-1")
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPost && r.URL.Path == "/sign":
+			var envelope struct {
+				Protected string `json:"protected"`
+				Payload   string `json:"payload"`
+				Signature string `json:"signature"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&envelope); err != nil {
+				t.Fatalf("decode envelope: %v", err)
+			}
+
+			headerBytes, err := base64.RawURLEncoding.DecodeString(envelope.Protected)
+			if err != nil {
+				t.Fatalf("decode protected header: %v", err)
+			}
+			var header jwsProtectedHeader
+			if err := json.Unmarshal(headerBytes, &header); err != nil {
+				t.Fatalf("unmarshal protected header: %v", err)
+			}
+			sawNonce = header.Nonce
+			if header.Alg != "ES256" {
+				t.Errorf("expected alg ES256, got %q", header.Alg)
+			}
+			if header.URL != server.URL+"/sign" {
+				t.Errorf("expected url bound to /sign, got %q", header.URL)
+			}
+
+			payloadBytes, err := base64.RawURLEncoding.DecodeString(envelope.Payload)
+			if err != nil {
+				t.Fatalf("decode payload: %v", err)
+			}
+			var payload struct {
+				CommitData string `json:"commitData"`
+			}
+			if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+				t.Fatalf("unmarshal payload: %v", err)
+			}
+			if payload.CommitData != "commit data" {
+				t.Errorf("expected commit data, got %q", payload.CommitData)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{"signature": "sig-result"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithRequestSigningKey(priv))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	result, err := c.Sign(context.Background(), "commit data", "")
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if result.Signature != "sig-result" {
+		t.Errorf("Signature = %q, want sig-result", result.Signature)
+	}
+	if sawNonce != "nonce-1" {
+		t.Errorf("expected server to observe nonce-1, got %q", sawNonce)
+	}
+}
+
+func TestSignRetriesOnceOnBadNonce(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead && r.URL.Path == "/nonce":
+			w.Header().Set("Replay-Nonce", "nonce-1")
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPost && r.URL.Path == "/sign":
+			attempts++
+			if attempts == 1 {
+				w.Header().Set("Replay-Nonce", "nonce-2")
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]string{"code": "BAD_NONCE", "error": "stale nonce"})
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{"signature": "sig-result"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithRequestSigningKey(priv))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	result, err := c.Sign(context.Background(), "commit data", "")
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if result.Signature != "sig-result" {
+		t.Errorf("Signature = %q, want sig-result", result.Signature)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestSignReturnsBadNonceErrorAfterExhaustedRetry(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead && r.URL.Path == "/nonce":
+			w.Header().Set("Replay-Nonce", "nonce-1")
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPost && r.URL.Path == "/sign":
+			w.Header().Set("Replay-Nonce", "nonce-2")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"code": "BAD_NONCE", "error": "stale nonce"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithRequestSigningKey(priv))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	_, err = c.Sign(context.Background(), "commit data", "")
+	if !IsBadNonceError(err) {
+		t.Errorf("expected BadNonceError, got %v", err)
+	}
+}
+
+func TestSignReturnsSignatureRejectedError(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead && r.URL.Path == "/nonce":
+			w.Header().Set("Replay-Nonce", "nonce-1")
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPost && r.URL.Path == "/sign":
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"code": "SIGNATURE_REJECTED", "error": "unknown kid"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithRequestSigningKey(priv))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	_, err = c.Sign(context.Background(), "commit data", "")
+	if !IsSignatureRejectedError(err) {
+		t.Errorf("expected SignatureRejectedError, got %v", err)
+	}
+}
+
+func TestWithJWSKeyUsesExplicitKIDAndVerifiableSignature(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	var nonces []string
+	var sawKID string
+	requestN := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead && r.URL.Path == "/nonce":
+			w.Header().Set("Replay-Nonce", "nonce-0")
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPost && r.URL.Path == "/sign":
+			requestN++
+
+			var envelope struct {
+				Protected string `json:"protected"`
+				Payload   string `json:"payload"`
+				Signature string `json:"signature"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&envelope); err != nil {
+				t.Fatalf("decode envelope: %v", err)
+			}
+
+			headerBytes, err := base64.RawURLEncoding.DecodeString(envelope.Protected)
+			if err != nil {
+				t.Fatalf("decode protected header: %v", err)
+			}
+			var header jwsProtectedHeader
+			if err := json.Unmarshal(headerBytes, &header); err != nil {
+				t.Fatalf("unmarshal protected header: %v", err)
+			}
+			sawKID = header.Kid
+			nonces = append(nonces, header.Nonce)
+
+			sig, err := base64.RawURLEncoding.DecodeString(envelope.Signature)
+			if err != nil {
+				t.Fatalf("decode signature: %v", err)
+			}
+			digest := sha256.Sum256([]byte(envelope.Protected + "." + envelope.Payload))
+			if !ecdsa.VerifyASN1(&priv.PublicKey, digest[:], sig) {
+				t.Error("signature does not verify against the signing key's public key")
+			}
+
+			nextNonce := fmt.Sprintf("nonce-%d", requestN)
+			w.Header().Set("Replay-Nonce", nextNonce)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{"signature": "sig-result"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithJWSKey(priv, "operator-key-1"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Sign(context.Background(), "commit data", ""); err != nil {
+			t.Fatalf("Sign %d: %v", i, err)
+		}
+	}
+
+	if sawKID != "operator-key-1" {
+		t.Errorf("kid = %q, want %q", sawKID, "operator-key-1")
+	}
+	if len(nonces) != 2 || nonces[0] == nonces[1] {
+		t.Errorf("expected a fresh nonce on each request, got %v", nonces)
+	}
+	if nonces[0] != "nonce-0" {
+		t.Errorf("first request nonce = %q, want the bootstrapped nonce-0", nonces[0])
+	}
+}
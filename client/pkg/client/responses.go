@@ -24,6 +24,15 @@ type SignResult struct {
 	Signature          string     `json:"signature"`
 	RateLimitRemaining *int       `json:"rateLimitRemaining,omitempty"`
 	RateLimitReset     *time.Time `json:"rateLimitReset,omitempty"`
+	// ContentSHA256 is set by SignStream to the client-computed digest
+	// of the streamed payload, for callers that want to cross-check it
+	// against their own record of what was signed.
+	ContentSHA256 string `json:"contentSha256,omitempty"`
+	// JobID is set instead of Signature when Sign is called with
+	// Async(true): the request was accepted but not yet completed, and
+	// this ID is passed to WaitSignature to retrieve the signature once
+	// the job finishes.
+	JobID string `json:"jobId,omitempty"`
 }
 
 // KeyInfo represents uploaded key information.
@@ -48,6 +57,16 @@ type AuditFilter struct {
 	Subject   string
 	StartDate time.Time
 	EndDate   time.Time
+	// Follow makes AuditLogs transparently upgrade to StreamAuditLogs:
+	// instead of returning the first page immediately, it keeps
+	// accumulating entries delivered over the stream until ctx is done,
+	// then returns everything collected so far. This lets CLI callers
+	// tail -f the audit trail through the same AuditLogs call site.
+	Follow bool
+	// Cursor resumes a paginated query from the opaque NextCursor value
+	// returned by a prior page. Used by AuditLogsIter and
+	// AuditLogsStream; AuditLogs and StreamAuditLogs ignore it.
+	Cursor string
 }
 
 // AuditLog represents a single audit log entry.
@@ -5,68 +5,407 @@ import (
 	"errors"
 	"math"
 	"math/rand/v2"
+	"net/http"
+	"strconv"
 	"time"
 )
 
+// Backoff computes how long to wait before the next retry attempt.
+// attempt is 0-indexed and counts completed attempts so far; prev is the
+// wait returned for the previous attempt (0 before the first retry),
+// which DecorrelatedJitterBackoff uses as its seed.
+type Backoff interface {
+	Next(attempt int, prev time.Duration) time.Duration
+}
+
+// ConstantBackoff always waits the same duration between attempts.
+type ConstantBackoff struct {
+	Wait time.Duration
+}
+
+// Next returns b.Wait unconditionally.
+func (b ConstantBackoff) Next(int, time.Duration) time.Duration {
+	return b.Wait
+}
+
+// ExponentialBackoff waits Base * Multiplier^attempt, capped at Max, plus
+// up to Base worth of jitter to avoid a thundering herd of synchronized
+// retries. Multiplier defaults to 2 when zero.
+type ExponentialBackoff struct {
+	Base       time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+// Next computes the exponential wait for attempt, ignoring prev.
+func (b ExponentialBackoff) Next(attempt int, _ time.Duration) time.Duration {
+	mult := b.Multiplier
+	if mult <= 0 {
+		mult = 2
+	}
+	if attempt > 20 {
+		attempt = 20 // avoid math.Pow overflow
+	}
+
+	wait := time.Duration(float64(b.Base) * math.Pow(mult, float64(attempt)))
+	if b.Base > 0 {
+		wait += time.Duration(rand.Int64N(int64(b.Base)))
+	}
+	if b.Max > 0 && wait > b.Max {
+		wait = b.Max
+	}
+	return wait
+}
+
+// jitteredBackoff waits Base * 2^attempt, capped at Max, plus up to
+// Jitter fraction of that wait added as random jitter. It backs
+// WithRetry's RetryConfig, which exposes Jitter as a 0-1 fraction
+// rather than ExponentialBackoff's fixed one-Base-worth-of-jitter.
+type jitteredBackoff struct {
+	Base   time.Duration
+	Max    time.Duration
+	Jitter float64
+}
+
+// Next computes the jittered exponential wait for attempt, ignoring prev.
+func (b jitteredBackoff) Next(attempt int, _ time.Duration) time.Duration {
+	if attempt > 20 {
+		attempt = 20 // avoid math.Pow overflow
+	}
+
+	wait := time.Duration(float64(b.Base) * math.Pow(2, float64(attempt)))
+	if b.Jitter > 0 {
+		jitterMax := int64(float64(wait) * b.Jitter)
+		if jitterMax > 0 {
+			wait += time.Duration(rand.Int64N(jitterMax))
+		}
+	}
+	if b.Max > 0 && wait > b.Max {
+		wait = b.Max
+	}
+	return wait
+}
+
+// DecorrelatedJitterBackoff implements the AWS Architecture Blog's
+// "decorrelated jitter" algorithm: sleep = min(Cap, random_between(Base,
+// prev*3)). It spreads retries out across a fleet of clients better than
+// a fixed exponential schedule. Base defaults to 1s and Cap to 30s when
+// zero.
+type DecorrelatedJitterBackoff struct {
+	Base time.Duration
+	Cap  time.Duration
+}
+
+// Next computes the next decorrelated-jitter wait given the previous
+// one.
+func (b DecorrelatedJitterBackoff) Next(_ int, prev time.Duration) time.Duration {
+	base := b.Base
+	if base <= 0 {
+		base = time.Second
+	}
+	cap := b.Cap
+	if cap <= 0 {
+		cap = 30 * time.Second
+	}
+
+	lo := int64(base)
+	hi := int64(prev) * 3
+	if hi <= lo {
+		hi = lo * 3
+	}
+
+	wait := time.Duration(lo + rand.Int64N(hi-lo+1))
+	if wait > cap {
+		wait = cap
+	}
+	return wait
+}
+
+// RetryPolicy decides whether a failed call should be retried and how
+// long to wait before the next attempt. attempt is 0-indexed and counts
+// completed attempts so far. Returning a zero wait lets the Retrier fall
+// back to its own exponential-backoff calculation.
+type RetryPolicy func(err error, attempt int) (retry bool, wait time.Duration)
+
+// RetryHook observes each retry decision, for logging/metrics. willRetry
+// is false on the final, non-retried attempt.
+type RetryHook func(attempt int, err error, willRetry bool, wait time.Duration)
+
+// OnRetryFunc is called once per retry, just before the Retrier sleeps
+// for nextWait. attempt is 1-based, counting the retry about to be made;
+// err is the error from the attempt that triggered it. Unlike RetryHook,
+// it only fires when a retry is actually about to happen (never on the
+// final failed attempt), and it carries ctx so callers can attach spans
+// or log fields scoped to the request in flight.
+type OnRetryFunc func(ctx context.Context, attempt int, err error, nextWait time.Duration)
+
+// OnGiveUpFunc is called exactly once, when the Retrier's loop exits
+// with a non-nil error: retries exhausted, a non-retryable error, or the
+// context was canceled. attempt is the 0-based index of the attempt that
+// produced err.
+type OnGiveUpFunc func(attempt int, err error)
+
+// RateLimitPolicy bounds how persistently the Retrier chases a
+// RateLimitError, independent of maxRetries. As long as the cumulative
+// Retry-After wait stays under MaxWait and the number of rate-limit
+// retries stays under MaxAttempts, a 429 keeps retrying even after the
+// general maxRetries counter would otherwise have given up. Non-429
+// failures are unaffected and keep using maxRetries as before.
+type RateLimitPolicy struct {
+	MaxWait     time.Duration
+	MaxAttempts int
+}
+
 // Retrier handles retry logic with exponential backoff.
 // It is safe for concurrent use by multiple goroutines.
 type Retrier struct {
-	maxRetries       int
-	retryWaitMin     time.Duration
-	retryWaitMax     time.Duration
-	retryOnRateLimit bool
+	maxRetries        int
+	retryWaitMin      time.Duration
+	retryWaitMax      time.Duration
+	retryOnRateLimit  bool
+	breaker           *circuitBreaker
+	policy            RetryPolicy
+	hook              RetryHook
+	backoffStrategy   Backoff
+	retryBackoffFunc  RetryBackoffFunc
+	onRetry           OnRetryFunc
+	onGiveUp          OnGiveUpFunc
+	rateLimitBudget   *RateLimitPolicy
+	hasIdempotencyKey bool
 }
 
 func newRetrier(opts *Options) *Retrier {
 	return &Retrier{
-		maxRetries:       opts.maxRetries,
-		retryWaitMin:     opts.retryWaitMin,
-		retryWaitMax:     opts.retryWaitMax,
-		retryOnRateLimit: opts.retryOnRateLimit,
+		maxRetries:        opts.maxRetries,
+		retryWaitMin:      opts.retryWaitMin,
+		retryWaitMax:      opts.retryWaitMax,
+		retryOnRateLimit:  opts.retryOnRateLimit,
+		breaker:           opts.breaker,
+		policy:            opts.retryPolicy,
+		hook:              opts.retryHook,
+		backoffStrategy:   opts.backoff,
+		retryBackoffFunc:  opts.retryBackoffFunc,
+		onRetry:           opts.onRetry,
+		onGiveUp:          opts.onGiveUp,
+		rateLimitBudget:   opts.rateLimitBudget,
+		hasIdempotencyKey: opts.idempotencyKeyFunc != nil,
 	}
 }
 
-// Do executes fn with retry logic.
+// Do executes fn with retry logic. If a circuit breaker is configured
+// and open, Do fails fast with ErrCircuitOpen without calling fn. fn is
+// assumed idempotent (safe to repeat after any failure); use
+// DoNonIdempotent for calls that write data and may not be.
 func (r *Retrier) Do(ctx context.Context, fn func() error) error {
+	return r.doWithBreaker(ctx, fn, true)
+}
+
+// DoNonIdempotent behaves like Do, but additionally refuses to retry a
+// request whose body may have been partially sent: a raw transport-level
+// error (no typed API error, meaning no response was ever received) is
+// only retried if an Idempotency-Key was configured via
+// WithIdempotencyKeyFunc. Errors that carry one of this package's typed
+// API errors (ServiceError, RateLimitError, ValidationError, AuthError)
+// indicate the server fully received and responded to the request, so
+// they retry under the same rules as Do.
+func (r *Retrier) DoNonIdempotent(ctx context.Context, fn func() error) error {
+	return r.doWithBreaker(ctx, fn, false)
+}
+
+func (r *Retrier) doWithBreaker(ctx context.Context, fn func() error, idempotent bool) error {
+	if r.breaker != nil {
+		if err := r.breaker.allow(); err != nil {
+			return err
+		}
+	}
+
+	err := r.do(ctx, fn, idempotent)
+
+	if r.breaker != nil {
+		r.breaker.recordResult(err)
+	}
+	return err
+}
+
+func (r *Retrier) do(ctx context.Context, fn func() error, idempotent bool) error {
 	var lastErr error
+	var prevWait time.Duration
+	var rlAttempts int
+	var rlWaitUsed time.Duration
+	var totalWait time.Duration
+	// skipBackoff is set after a server-directed Retry-After wait has
+	// already been slept at the bottom of an iteration, so the next
+	// iteration's top-of-loop backoff is skipped entirely instead of
+	// stacking an additional exponential wait on top of it.
+	var skipBackoff bool
+
+	giveUp := func(attempt int, err error) error {
+		if r.onGiveUp != nil {
+			r.onGiveUp(attempt, err)
+		}
+		return err
+	}
 
-	for attempt := 0; attempt <= r.maxRetries; attempt++ {
-		// Exponential backoff before retry (skip on first attempt)
-		if attempt > 0 {
-			wait := r.backoff(attempt)
+	// aborted builds the error returned when ctx is canceled mid-wait,
+	// wrapping lastErr, the attempt count, and the cumulative wait so far
+	// instead of surfacing a bare ctx.Err().
+	aborted := func(attempt int) error {
+		return &RetryAbortedError{
+			LastErr:  lastErr,
+			Attempts: attempt,
+			Elapsed:  totalWait,
+			CtxErr:   ctx.Err(),
+		}
+	}
+
+	// attempt has no upper bound in the loop header: the general
+	// maxRetries budget is enforced in the body below, alongside the
+	// separate rate-limit budget (rlAttempts/rlWaitUsed against
+	// rateLimitBudget) that lets a RateLimitError keep retrying past
+	// maxRetries.
+	for attempt := 0; ; attempt++ {
+		// Exponential backoff before retry (skip on first attempt, and
+		// skip entirely when the previous iteration already slept a
+		// server-directed Retry-After in place of it).
+		if attempt > 0 && !skipBackoff {
+			wait, ok := r.backoffForAttempt(attempt, prevWait, lastErr)
+			if !ok {
+				return giveUp(attempt-1, lastErr)
+			}
+			prevWait = wait
+			totalWait += wait
+			if r.onRetry != nil {
+				r.onRetry(ctx, attempt, lastErr, wait)
+			}
 			select {
 			case <-time.After(wait):
 			case <-ctx.Done():
-				return ctx.Err()
+				return giveUp(attempt-1, aborted(attempt))
 			}
 		}
+		skipBackoff = false
 
 		lastErr = fn()
 		if lastErr == nil {
 			return nil
 		}
 
-		// Check if we should retry
-		if !r.shouldRetry(lastErr) {
-			return lastErr
+		retry, wait := r.decide(lastErr, attempt)
+		if retry && !idempotent && !r.safeToRetryNonIdempotent(lastErr) {
+			retry = false
 		}
 
-		// Handle rate limit with explicit wait
+		// A RateLimitError consumes its own budget (rlAttempts/rlWaitUsed
+		// against rateLimitBudget) instead of the general maxRetries
+		// counter, once a rate-limit policy is configured -- a bounded
+		// Retry-After is worth waiting out even past maxRetries.
+		// Everything else keeps using maxRetries as before.
 		var rateLimitErr *RateLimitError
-		if errors.As(lastErr, &rateLimitErr) && r.retryOnRateLimit {
-			if rateLimitErr.RetryAfter > 0 {
-				timer := time.NewTimer(rateLimitErr.RetryAfter)
-				defer timer.Stop()
-				select {
-				case <-timer.C:
-				case <-ctx.Done():
-					return ctx.Err()
-				}
+		onRateLimitBudget := r.rateLimitBudget != nil && errors.As(lastErr, &rateLimitErr)
+
+		var withinBudget bool
+		if onRateLimitBudget {
+			withinBudget = rlAttempts < r.rateLimitBudget.MaxAttempts &&
+				rlWaitUsed+wait <= r.rateLimitBudget.MaxWait
+		} else {
+			withinBudget = attempt < r.maxRetries
+		}
+
+		retry = retry && withinBudget
+		if r.hook != nil {
+			r.hook(attempt, lastErr, retry, wait)
+		}
+		if !retry {
+			return giveUp(attempt, lastErr)
+		}
+
+		if onRateLimitBudget {
+			rlAttempts++
+			rlWaitUsed += wait
+		}
+
+		// wait > 0 here only when decide() returned a server-directed
+		// Retry-After (the default policy otherwise returns 0 and lets
+		// the top-of-loop backoff handle it); skipBackoff makes sure the
+		// next iteration doesn't stack its own backoff on top of it.
+		if wait > 0 {
+			skipBackoff = true
+			totalWait += wait
+			if r.onRetry != nil {
+				r.onRetry(ctx, attempt+1, lastErr, wait)
 			}
+			timer := time.NewTimer(wait)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return giveUp(attempt, aborted(attempt+1))
+			}
+		}
+	}
+}
+
+// safeToRetryNonIdempotent reports whether a non-idempotent call can be
+// retried after lastErr. A raw transport error without an Idempotency-Key
+// configured is treated conservatively as unsafe, since this layer can't
+// tell whether the request body was partially written before the
+// connection failed.
+func (r *Retrier) safeToRetryNonIdempotent(lastErr error) bool {
+	if r.hasIdempotencyKey {
+		return true
+	}
+	return isTypedAPIError(lastErr)
+}
+
+func isTypedAPIError(err error) bool {
+	var serviceErr *ServiceError
+	var rateLimitErr *RateLimitError
+	var validationErr *ValidationError
+	var authErr *AuthError
+	switch {
+	case errors.As(err, &serviceErr):
+		return true
+	case errors.As(err, &rateLimitErr):
+		return true
+	case errors.As(err, &validationErr):
+		return true
+	case errors.As(err, &authErr):
+		return true
+	default:
+		return false
+	}
+}
+
+// decide reports whether lastErr should be retried and how long to wait
+// before the next attempt. A non-zero wait is a server-directed delay
+// (e.g. Retry-After) that replaces the standard backoff the loop would
+// otherwise apply at the top of its next iteration, rather than
+// stacking on top of it. If a custom RetryPolicy is configured it takes
+// precedence; otherwise the built-in rules apply.
+func (r *Retrier) decide(lastErr error, attempt int) (bool, time.Duration) {
+	if r.policy != nil {
+		return r.policy(lastErr, attempt)
+	}
+
+	if !r.shouldRetry(lastErr) {
+		return false, 0
+	}
+
+	var rateLimitErr *RateLimitError
+	if errors.As(lastErr, &rateLimitErr) && r.retryOnRateLimit && rateLimitErr.RetryAfter > 0 {
+		return true, rateLimitErr.RetryAfter
+	}
+
+	var serviceErr *ServiceError
+	if errors.As(lastErr, &serviceErr) && serviceErr.RetryAfter > 0 {
+		wait := serviceErr.RetryAfter
+		if wait > r.retryWaitMax {
+			wait = r.retryWaitMax
 		}
+		return true, wait
 	}
 
-	return lastErr
+	return true, 0
 }
 
 func (r *Retrier) shouldRetry(err error) bool {
@@ -97,18 +436,69 @@ func (r *Retrier) shouldRetry(err error) bool {
 	return false
 }
 
-func (r *Retrier) backoff(attempt int) time.Duration {
+// backoffForAttempt returns the wait before the given retry attempt, and
+// false if retrying should stop now instead. false is only possible when
+// a RetryBackoffFunc is configured (via WithRetryBackoff) and it returns
+// a non-positive duration for this attempt; the built-in backoff and a
+// plain Backoff strategy (via WithBackoff) always keep retrying until
+// maxRetries is exhausted, even if they return a zero wait.
+func (r *Retrier) backoffForAttempt(attempt int, prevWait time.Duration, lastErr error) (time.Duration, bool) {
+	if r.retryBackoffFunc != nil {
+		wait := r.retryBackoffFunc(attempt, responseForError(lastErr), lastErr)
+		return wait, wait > 0
+	}
+	return r.backoff(attempt, prevWait), true
+}
+
+// responseForError reconstructs the minimal *http.Response a
+// RetryBackoffFunc needs to branch on resp.StatusCode, from the typed
+// API error the Retrier actually has in hand (the real *http.Response
+// body is already drained and discarded by the time an error reaches
+// here). It returns nil for AuthError, ValidationError, and raw
+// transport errors, none of which carry a status code.
+func responseForError(err error) *http.Response {
+	var serviceErr *ServiceError
+	if errors.As(err, &serviceErr) {
+		return &http.Response{StatusCode: serviceErr.StatusCode}
+	}
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return &http.Response{StatusCode: http.StatusTooManyRequests}
+	}
+	return nil
+}
+
+// minBackoffFloor is the smallest base wait the built-in backoff will ever
+// use. WithRetryWait accepts a retryWaitMin of 0 (or less) without
+// complaint -- validation happens at client creation, not option
+// application -- and a zero base would make backoff return a zero
+// duration (a busy-loop retry storm) and make rand.Int64N panic on a
+// zero jitter range. Substituting this floor for retryWaitMin keeps both
+// cases well-defined while still retrying about as fast as the caller
+// asked for.
+const minBackoffFloor = 1 * time.Millisecond
+
+func (r *Retrier) backoff(attempt int, prevWait time.Duration) time.Duration {
+	if r.backoffStrategy != nil {
+		return r.backoffStrategy.Next(attempt, prevWait)
+	}
+
 	// Cap attempt to prevent overflow
 	if attempt > 10 {
 		attempt = 10
 	}
 
+	base := r.retryWaitMin
+	if base <= 0 {
+		base = minBackoffFloor
+	}
+
 	// Exponential backoff with jitter
 	mult := math.Pow(2, float64(attempt))
-	wait := time.Duration(mult) * r.retryWaitMin
+	wait := time.Duration(mult) * base
 
-	// Add jitter (0-100% of retryWaitMin) - using math/rand/v2 (goroutine-safe)
-	jitter := time.Duration(rand.Int64N(int64(r.retryWaitMin)))
+	// Add jitter (0-100% of base) - using math/rand/v2 (goroutine-safe)
+	jitter := time.Duration(rand.Int64N(int64(base)))
 	wait += jitter
 
 	if wait > r.retryWaitMax {
@@ -117,3 +507,66 @@ func (r *Retrier) backoff(attempt int) time.Duration {
 
 	return wait
 }
+
+// ConservativeServiceRetryPolicy returns a RetryPolicy for deployments
+// that want narrower retry rules than the Retrier's default shouldRetry:
+// a RateLimitError always retries after exactly its RetryAfter duration,
+// a ServiceError retries only on 502, 503, or 504 (never 501, which
+// signals a permanent "not implemented" rather than a transient
+// condition), and auth/validation/key-not-found errors never retry. Any
+// other error (e.g. a raw transport error) is retried, deferring further
+// gating to the Retrier's idempotency rules.
+func ConservativeServiceRetryPolicy() RetryPolicy {
+	return func(err error, _ int) (bool, time.Duration) {
+		var rateLimitErr *RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			return true, rateLimitErr.RetryAfter
+		}
+
+		var serviceErr *ServiceError
+		if errors.As(err, &serviceErr) {
+			switch serviceErr.StatusCode {
+			case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				return true, 0
+			default:
+				return false, 0
+			}
+		}
+
+		var authErr *AuthError
+		var validationErr *ValidationError
+		if errors.As(err, &authErr) || errors.As(err, &validationErr) {
+			return false, 0
+		}
+		if IsKeyNotFound(err) {
+			return false, 0
+		}
+
+		return true, 0
+	}
+}
+
+// parseRetryAfter parses a Retry-After header value in either the
+// integer-seconds form or the HTTP-date form (RFC 9110 ยง10.2.3).
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	when, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	wait := time.Until(when)
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
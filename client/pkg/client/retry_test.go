@@ -0,0 +1,819 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfterSeconds(t *testing.T) {
+	d, ok := parseRetryAfter("5")
+	if !ok || d != 5*time.Second {
+		t.Errorf("expected 5s, true; got %v, %v", d, ok)
+	}
+}
+
+func TestParseRetryAfterHTTPDate(t *testing.T) {
+	when := time.Now().Add(10 * time.Second).UTC().Format(time.RFC1123)
+	d, ok := parseRetryAfter(when)
+	if !ok {
+		t.Fatal("expected to parse HTTP-date Retry-After")
+	}
+	if d <= 0 || d > 11*time.Second {
+		t.Errorf("expected ~10s, got %v", d)
+	}
+}
+
+func TestParseRetryAfterInvalid(t *testing.T) {
+	if _, ok := parseRetryAfter("not-a-value"); ok {
+		t.Error("expected invalid Retry-After to fail parsing")
+	}
+}
+
+func TestRetrierCustomPolicyOverridesDefault(t *testing.T) {
+	var attempts int
+	policy := RetryPolicy(func(_ error, attempt int) (bool, time.Duration) {
+		return attempt < 1, 0
+	})
+
+	r := &Retrier{maxRetries: 5, retryWaitMin: time.Millisecond, retryWaitMax: time.Millisecond, policy: policy}
+	err := r.Do(context.Background(), func() error {
+		attempts++
+		return &ValidationError{Code: "X", Message: "always fails, but policy allows one retry"}
+	})
+
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts (1 retry), got %d", attempts)
+	}
+	if !IsValidationError(err) {
+		t.Errorf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestRetrierHookObservesDecisions(t *testing.T) {
+	var seen []bool
+	hook := RetryHook(func(_ int, _ error, willRetry bool, _ time.Duration) {
+		seen = append(seen, willRetry)
+	})
+
+	r := &Retrier{maxRetries: 1, retryWaitMin: time.Millisecond, retryWaitMax: time.Millisecond, hook: hook}
+	_ = r.Do(context.Background(), func() error {
+		return &ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500}
+	})
+
+	if len(seen) != 2 {
+		t.Fatalf("expected 2 hook invocations, got %d", len(seen))
+	}
+	if !seen[0] || seen[1] {
+		t.Errorf("expected [true, false], got %v", seen)
+	}
+}
+
+func TestRetrierOnRetryFiresOncePerRetryBeforeSleep(t *testing.T) {
+	var attempts []int
+	var errs []error
+	r := &Retrier{
+		maxRetries:   2,
+		retryWaitMin: time.Millisecond,
+		retryWaitMax: time.Millisecond,
+		onRetry: func(ctx context.Context, attempt int, err error, nextWait time.Duration) {
+			if ctx == nil {
+				t.Error("expected non-nil ctx")
+			}
+			attempts = append(attempts, attempt)
+			errs = append(errs, err)
+		},
+	}
+
+	_ = r.Do(context.Background(), func() error {
+		return &ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500}
+	})
+
+	if len(attempts) != 2 {
+		t.Fatalf("expected onRetry called twice (once per retry), got %d: %v", len(attempts), attempts)
+	}
+	if attempts[0] != 1 || attempts[1] != 2 {
+		t.Errorf("expected attempts [1, 2], got %v", attempts)
+	}
+	for _, err := range errs {
+		var serviceErr *ServiceError
+		if !errors.As(err, &serviceErr) {
+			t.Errorf("expected onRetry err to be a *ServiceError, got %v", err)
+		}
+	}
+}
+
+func TestRetrierOnGiveUpFiresOnceWhenRetriesExhausted(t *testing.T) {
+	var calls int
+	var lastAttempt int
+	r := &Retrier{
+		maxRetries:   2,
+		retryWaitMin: time.Millisecond,
+		retryWaitMax: time.Millisecond,
+		onGiveUp: func(attempt int, err error) {
+			calls++
+			lastAttempt = attempt
+		},
+	}
+
+	err := r.Do(context.Background(), func() error {
+		return &ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500}
+	})
+
+	if calls != 1 {
+		t.Fatalf("expected onGiveUp called exactly once, got %d", calls)
+	}
+	if lastAttempt != 2 {
+		t.Errorf("expected give-up on attempt 2, got %d", lastAttempt)
+	}
+	if err == nil {
+		t.Error("expected non-nil error")
+	}
+}
+
+func TestRetrierOnGiveUpDoesNotFireOnSuccess(t *testing.T) {
+	var calls int
+	r := &Retrier{
+		maxRetries:   2,
+		retryWaitMin: time.Millisecond,
+		retryWaitMax: time.Millisecond,
+		onGiveUp: func(int, error) {
+			calls++
+		},
+	}
+
+	err := r.Do(context.Background(), func() error { return nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected onGiveUp not to fire on success, got %d calls", calls)
+	}
+}
+
+func TestWithOnRetryAndWithOnGiveUpSetOptions(t *testing.T) {
+	opts := defaultOptions()
+	WithOnRetry(func(context.Context, int, error, time.Duration) {})(opts)
+	WithOnGiveUp(func(int, error) {})(opts)
+
+	if opts.onRetry == nil {
+		t.Error("expected onRetry to be set")
+	}
+	if opts.onGiveUp == nil {
+		t.Error("expected onGiveUp to be set")
+	}
+}
+
+func TestWithRateLimitPolicySetsOption(t *testing.T) {
+	opts := defaultOptions()
+	WithRateLimitPolicy(time.Minute, 10)(opts)
+
+	if opts.rateLimitBudget == nil {
+		t.Fatal("expected rateLimitBudget to be set")
+	}
+	if opts.rateLimitBudget.MaxWait != time.Minute || opts.rateLimitBudget.MaxAttempts != 10 {
+		t.Errorf("got %+v, want {1m, 10}", opts.rateLimitBudget)
+	}
+}
+
+func TestRetrierRateLimitBudgetOutlastsMaxRetries(t *testing.T) {
+	var calls int
+	r := &Retrier{
+		maxRetries:       1,
+		retryWaitMin:     time.Millisecond,
+		retryWaitMax:     time.Millisecond,
+		retryOnRateLimit: true,
+		rateLimitBudget:  &RateLimitPolicy{MaxWait: time.Second, MaxAttempts: 5},
+	}
+
+	err := r.Do(context.Background(), func() error {
+		calls++
+		if calls <= 4 {
+			return &RateLimitError{Message: "slow down", RetryAfter: time.Millisecond}
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 5 {
+		t.Errorf("expected 5 calls (4 rate-limited + 1 success), got %d", calls)
+	}
+}
+
+func TestRetrierRateLimitBudgetStopsAtMaxAttempts(t *testing.T) {
+	var calls int
+	r := &Retrier{
+		maxRetries:       1,
+		retryWaitMin:     time.Millisecond,
+		retryWaitMax:     time.Millisecond,
+		retryOnRateLimit: true,
+		rateLimitBudget:  &RateLimitPolicy{MaxWait: time.Minute, MaxAttempts: 2},
+	}
+
+	err := r.Do(context.Background(), func() error {
+		calls++
+		return &RateLimitError{Message: "slow down", RetryAfter: time.Millisecond}
+	})
+
+	if calls != 3 {
+		t.Errorf("expected 3 calls (1 initial + 2 budgeted retries), got %d", calls)
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected a rate-limit error, got %v", err)
+	}
+}
+
+func TestRetrierRateLimitBudgetStopsAtMaxWait(t *testing.T) {
+	var calls int
+	r := &Retrier{
+		maxRetries:       1,
+		retryWaitMin:     time.Millisecond,
+		retryWaitMax:     time.Millisecond,
+		retryOnRateLimit: true,
+		rateLimitBudget:  &RateLimitPolicy{MaxWait: 5 * time.Millisecond, MaxAttempts: 100},
+	}
+
+	err := r.Do(context.Background(), func() error {
+		calls++
+		return &RateLimitError{Message: "slow down", RetryAfter: 3 * time.Millisecond}
+	})
+
+	if calls != 2 {
+		t.Errorf("expected 2 calls (1 initial + 1 retry within the 5ms budget), got %d", calls)
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected a rate-limit error, got %v", err)
+	}
+}
+
+func TestRetrierRateLimitBudgetBoundsActualElapsedWait(t *testing.T) {
+	const retryAfter = 5 * time.Millisecond
+	const maxWait = 25 * time.Millisecond
+	r := &Retrier{
+		retryOnRateLimit: true,
+		backoffStrategy:  ConstantBackoff{Wait: 300 * time.Millisecond}, // must never fire
+		rateLimitBudget:  &RateLimitPolicy{MaxWait: maxWait, MaxAttempts: 100},
+	}
+
+	start := time.Now()
+	err := r.Do(context.Background(), func() error {
+		return &RateLimitError{Message: "slow down", RetryAfter: retryAfter}
+	})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected a rate-limit error, got %v", err)
+	}
+	// rlWaitUsed only ever accounts for the server-directed waits, so
+	// actual elapsed time must track it closely. Before the skipBackoff
+	// fix, the loop also slept the 300ms backoffStrategy between every
+	// retry, so this would have blown well past maxWait.
+	if max := maxWait + 100*time.Millisecond; elapsed > max {
+		t.Errorf("elapsed = %v, want < %v (backoff must not run alongside rate-limit waits)", elapsed, max)
+	}
+}
+
+func TestRetrierNonRateLimitErrorsStillUseMaxRetriesWithBudgetConfigured(t *testing.T) {
+	var calls int
+	r := &Retrier{
+		maxRetries:      1,
+		retryWaitMin:    time.Millisecond,
+		retryWaitMax:    time.Millisecond,
+		rateLimitBudget: &RateLimitPolicy{MaxWait: time.Minute, MaxAttempts: 100},
+	}
+
+	err := r.Do(context.Background(), func() error {
+		calls++
+		return &ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500}
+	})
+
+	if calls != 2 {
+		t.Errorf("expected 2 calls (1 initial + 1 retry, capped by maxRetries), got %d", calls)
+	}
+	if err == nil {
+		t.Error("expected non-nil error")
+	}
+}
+
+func TestRetrierDefaultDecisionMatchesShouldRetry(t *testing.T) {
+	r := &Retrier{retryWaitMin: time.Millisecond, retryWaitMax: time.Second, retryOnRateLimit: true}
+
+	retry, _ := r.decide(&ServiceError{Code: "X", StatusCode: 500}, 0)
+	if !retry {
+		t.Error("expected 5xx ServiceError to be retried")
+	}
+
+	retry, _ = r.decide(errors.New("plain error"), 0)
+	if retry {
+		t.Error("expected unknown error not to be retried")
+	}
+}
+
+func TestConstantBackoffAlwaysReturnsWait(t *testing.T) {
+	b := ConstantBackoff{Wait: 250 * time.Millisecond}
+	if got := b.Next(0, 0); got != 250*time.Millisecond {
+		t.Errorf("attempt 0: got %v", got)
+	}
+	if got := b.Next(9, 9*time.Second); got != 250*time.Millisecond {
+		t.Errorf("attempt 9: got %v", got)
+	}
+}
+
+func TestExponentialBackoffGrowsAndCaps(t *testing.T) {
+	b := ExponentialBackoff{Base: 100 * time.Millisecond, Max: time.Second, Multiplier: 2}
+
+	first := b.Next(0, 0)
+	if first < 100*time.Millisecond || first > 200*time.Millisecond {
+		t.Errorf("attempt 0: expected ~100-200ms, got %v", first)
+	}
+
+	capped := b.Next(10, 0)
+	if capped > time.Second {
+		t.Errorf("expected attempt 10 to be capped at 1s, got %v", capped)
+	}
+}
+
+func TestDecorrelatedJitterBackoffStaysWithinBounds(t *testing.T) {
+	b := DecorrelatedJitterBackoff{Base: 100 * time.Millisecond, Cap: time.Second}
+
+	prev := time.Duration(0)
+	for i := 0; i < 20; i++ {
+		wait := b.Next(i, prev)
+		if wait < b.Base {
+			t.Fatalf("attempt %d: wait %v below base %v", i, wait, b.Base)
+		}
+		if wait > b.Cap {
+			t.Fatalf("attempt %d: wait %v exceeds cap %v", i, wait, b.Cap)
+		}
+		prev = wait
+	}
+}
+
+func TestConservativeServiceRetryPolicy(t *testing.T) {
+	policy := ConservativeServiceRetryPolicy()
+
+	tests := []struct {
+		name      string
+		err       error
+		wantRetry bool
+	}{
+		{"retries 502", &ServiceError{Code: "X", StatusCode: 502}, true},
+		{"retries 503", &ServiceError{Code: "X", StatusCode: 503}, true},
+		{"retries 504", &ServiceError{Code: "X", StatusCode: 504}, true},
+		{"never retries 501", &ServiceError{Code: "X", StatusCode: 501}, false},
+		{"never retries 500", &ServiceError{Code: "X", StatusCode: 500}, false},
+		{"never retries auth errors", &AuthError{Code: "UNAUTHORIZED", Message: "bad token"}, false},
+		{"never retries validation errors", &ValidationError{Code: "INVALID", Message: "bad input"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry, _ := policy(tt.err, 0)
+			if retry != tt.wantRetry {
+				t.Errorf("retry = %v, want %v", retry, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestConservativeServiceRetryPolicyHonorsRateLimitRetryAfter(t *testing.T) {
+	policy := ConservativeServiceRetryPolicy()
+
+	retry, wait := policy(&RateLimitError{RetryAfter: 7 * time.Second}, 0)
+	if !retry {
+		t.Error("expected RateLimitError to always retry")
+	}
+	if wait != 7*time.Second {
+		t.Errorf("wait = %v, want 7s", wait)
+	}
+}
+
+func TestRetrierWithBackoffOverridesDefaultSchedule(t *testing.T) {
+	var waits []time.Duration
+	r := &Retrier{
+		maxRetries:      2,
+		retryWaitMin:    time.Millisecond,
+		retryWaitMax:    time.Millisecond,
+		backoffStrategy: ConstantBackoff{Wait: time.Millisecond},
+		hook: func(_ int, _ error, _ bool, wait time.Duration) {
+			waits = append(waits, wait)
+		},
+	}
+
+	_ = r.Do(context.Background(), func() error {
+		return &ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500}
+	})
+
+	if len(waits) == 0 {
+		t.Fatal("expected at least one hook invocation")
+	}
+}
+
+func TestRetrierZeroRetryWaitMinMakesForwardProgressWithoutPanicking(t *testing.T) {
+	var attempts int
+	r := &Retrier{
+		maxRetries:   3,
+		retryWaitMin: 0,
+		retryWaitMax: time.Second,
+	}
+
+	err := r.Do(context.Background(), func() error {
+		attempts++
+		return &ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500}
+	})
+
+	if attempts != 4 {
+		t.Errorf("attempts = %d, want 4 (initial + 3 retries)", attempts)
+	}
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) {
+		t.Errorf("expected final error to be a *ServiceError, got %v", err)
+	}
+}
+
+func TestBackoffWithZeroRetryWaitMinUsesFloor(t *testing.T) {
+	r := &Retrier{retryWaitMin: 0, retryWaitMax: time.Second}
+
+	wait := r.backoff(0, 0)
+	if wait <= 0 {
+		t.Errorf("backoff with zero retryWaitMin returned non-positive wait: %v", wait)
+	}
+}
+
+func TestDecideHonorsServiceErrorRetryAfter(t *testing.T) {
+	r := &Retrier{retryWaitMax: 10 * time.Second}
+
+	retry, wait := r.decide(&ServiceError{Code: "X", StatusCode: 503, RetryAfter: 3 * time.Second}, 0)
+	if !retry {
+		t.Error("expected 503 with RetryAfter to retry")
+	}
+	if wait != 3*time.Second {
+		t.Errorf("wait = %v, want 3s", wait)
+	}
+}
+
+func TestDecideCapsServiceErrorRetryAfterAtRetryWaitMax(t *testing.T) {
+	r := &Retrier{retryWaitMax: 2 * time.Second}
+
+	_, wait := r.decide(&ServiceError{Code: "X", StatusCode: 503, RetryAfter: 30 * time.Second}, 0)
+	if wait != 2*time.Second {
+		t.Errorf("wait = %v, want capped at retryWaitMax (2s)", wait)
+	}
+}
+
+func TestDoDoesNotStackBackoffOnTopOfServerRetryAfter(t *testing.T) {
+	const retryAfter = 20 * time.Millisecond
+	const backoffWait = 200 * time.Millisecond // large enough to expose stacking if it regresses
+	r := &Retrier{
+		maxRetries:      2,
+		retryWaitMax:    time.Second,
+		backoffStrategy: ConstantBackoff{Wait: backoffWait},
+	}
+
+	attempts := 0
+	start := time.Now()
+	err := r.Do(context.Background(), func() error {
+		attempts++
+		if attempts < 3 {
+			return &ServiceError{Code: "X", StatusCode: 503, RetryAfter: retryAfter}
+		}
+		return nil
+	})
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+	// Two retries, each should wait only the server's retryAfter, not
+	// retryAfter+backoffWait stacked on top of it; give generous slack
+	// for scheduling jitter while still catching the regression.
+	if max := 2 * (retryAfter + 50*time.Millisecond); elapsed > max {
+		t.Errorf("elapsed = %v, want < %v (backoff must not stack on top of Retry-After)", elapsed, max)
+	}
+}
+
+func TestWithRetryBackoffSetsOption(t *testing.T) {
+	opts := defaultOptions()
+	fn := RetryBackoffFunc(func(int, *http.Response, error) time.Duration { return time.Second })
+	WithRetryBackoff(fn)(opts)
+	if opts.retryBackoffFunc == nil {
+		t.Fatal("expected retryBackoffFunc to be set")
+	}
+}
+
+func TestRetrierRetryBackoffFuncOverridesBackoffStrategy(t *testing.T) {
+	var gotAttempts []int
+	r := &Retrier{
+		maxRetries:      3,
+		backoffStrategy: ConstantBackoff{Wait: time.Hour}, // would time out the test if honored
+		retryBackoffFunc: func(attempt int, resp *http.Response, err error) time.Duration {
+			gotAttempts = append(gotAttempts, attempt)
+			if resp == nil || resp.StatusCode != 500 {
+				t.Errorf("expected resp.StatusCode 500, got %v", resp)
+			}
+			if !IsServiceError(err) {
+				t.Errorf("expected a ServiceError, got %v", err)
+			}
+			return time.Microsecond
+		},
+	}
+
+	attempts := 0
+	err := r.Do(context.Background(), func() error {
+		attempts++
+		return &ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500}
+	})
+
+	if !IsServiceError(err) {
+		t.Errorf("expected ServiceError after exhausting retries, got %v", err)
+	}
+	if attempts != 4 {
+		t.Errorf("attempts = %d, want 4 (1 + maxRetries)", attempts)
+	}
+	if want := []int{1, 2, 3}; len(gotAttempts) != len(want) || gotAttempts[0] != want[0] || gotAttempts[1] != want[1] || gotAttempts[2] != want[2] {
+		t.Errorf("gotAttempts = %v, want %v", gotAttempts, want)
+	}
+}
+
+func TestResponseForError(t *testing.T) {
+	if resp := responseForError(&ServiceError{Code: "X", StatusCode: 503}); resp == nil || resp.StatusCode != 503 {
+		t.Errorf("ServiceError: resp = %v, want StatusCode 503", resp)
+	}
+	if resp := responseForError(&RateLimitError{Message: "too many"}); resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("RateLimitError: resp = %v, want StatusCode 429", resp)
+	}
+	if resp := responseForError(&AuthError{Code: "X", Message: "no"}); resp != nil {
+		t.Errorf("AuthError: resp = %v, want nil", resp)
+	}
+	if resp := responseForError(&ValidationError{Code: "X", Message: "bad"}); resp != nil {
+		t.Errorf("ValidationError: resp = %v, want nil", resp)
+	}
+	if resp := responseForError(errors.New("boom")); resp != nil {
+		t.Errorf("raw transport error: resp = %v, want nil", resp)
+	}
+}
+
+func TestRetrierRetryBackoffFuncSeesStatusCodeForRateLimitError(t *testing.T) {
+	var gotResp *http.Response
+	r := &Retrier{
+		maxRetries:       1,
+		retryOnRateLimit: true,
+		retryBackoffFunc: func(attempt int, resp *http.Response, err error) time.Duration {
+			gotResp = resp
+			return time.Microsecond
+		},
+	}
+
+	_ = r.Do(context.Background(), func() error {
+		return &RateLimitError{Message: "slow down"}
+	})
+
+	if gotResp == nil || gotResp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("resp = %v, want StatusCode 429", gotResp)
+	}
+}
+
+func TestRetrierRetryBackoffFuncAbortsOnNonPositiveWait(t *testing.T) {
+	attempts := 0
+	r := &Retrier{
+		maxRetries: 5,
+		retryBackoffFunc: func(attempt int, _ *http.Response, _ error) time.Duration {
+			if attempt >= 2 {
+				return 0
+			}
+			return time.Microsecond
+		},
+	}
+
+	err := r.Do(context.Background(), func() error {
+		attempts++
+		return &ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500}
+	})
+
+	if !IsServiceError(err) {
+		t.Errorf("expected ServiceError surfaced immediately, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2 (stopped once retryBackoffFunc returned <= 0)", attempts)
+	}
+}
+
+func TestDoNonIdempotentRetriesTypedAPIError(t *testing.T) {
+	var attempts int
+	r := &Retrier{maxRetries: 2, retryWaitMin: time.Millisecond, retryWaitMax: time.Millisecond}
+
+	err := r.DoNonIdempotent(context.Background(), func() error {
+		attempts++
+		return &ServiceError{Code: "INTERNAL_ERROR", StatusCode: 500}
+	})
+
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts for a typed API error, got %d", attempts)
+	}
+	if !IsServiceError(err) {
+		t.Errorf("expected ServiceError, got %v", err)
+	}
+}
+
+func TestDoNonIdempotentDoesNotRetryRawTransportErrorWithoutKey(t *testing.T) {
+	var attempts int
+	r := &Retrier{maxRetries: 2, retryWaitMin: time.Millisecond, retryWaitMax: time.Millisecond}
+
+	// A plain error with no typed API error wrapped inside it models a
+	// raw transport-level failure where no response was ever received.
+	wantErr := errors.New("connection reset by peer")
+	r.policy = func(_ error, _ int) (bool, time.Duration) { return true, 0 }
+
+	err := r.DoNonIdempotent(context.Background(), func() error {
+		attempts++
+		return wantErr
+	})
+
+	if attempts != 1 {
+		t.Errorf("expected exactly 1 attempt (no retry of a partial write), got %d", attempts)
+	}
+	if !errors.Is(err, wantErr) {
+		t.Errorf("expected original error to propagate, got %v", err)
+	}
+}
+
+func TestDoNonIdempotentRetriesRawTransportErrorWithIdempotencyKey(t *testing.T) {
+	var attempts int
+	r := &Retrier{maxRetries: 2, retryWaitMin: time.Millisecond, retryWaitMax: time.Millisecond, hasIdempotencyKey: true}
+	r.policy = func(_ error, _ int) (bool, time.Duration) { return true, 0 }
+
+	_ = r.DoNonIdempotent(context.Background(), func() error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("connection reset by peer")
+		}
+		return nil
+	})
+
+	if attempts != 2 {
+		t.Errorf("expected retry to succeed on 2nd attempt with idempotency key set, got %d attempts", attempts)
+	}
+}
+
+func TestJitteredBackoffGrowsCapsAndStaysWithinJitterBound(t *testing.T) {
+	b := jitteredBackoff{Base: 100 * time.Millisecond, Max: time.Second, Jitter: 0.5}
+
+	first := b.Next(0, 0)
+	if first < 100*time.Millisecond || first > 150*time.Millisecond {
+		t.Errorf("attempt 0: expected 100-150ms, got %v", first)
+	}
+
+	capped := b.Next(10, 0)
+	if capped > time.Second {
+		t.Errorf("expected attempt 10 to be capped at 1s, got %v", capped)
+	}
+}
+
+func TestJitteredBackoffZeroJitterIsDeterministic(t *testing.T) {
+	b := jitteredBackoff{Base: 50 * time.Millisecond, Max: time.Second}
+
+	if got := b.Next(0, 0); got != 50*time.Millisecond {
+		t.Errorf("attempt 0: expected exactly 50ms with no jitter, got %v", got)
+	}
+	if got := b.Next(2, 0); got != 200*time.Millisecond {
+		t.Errorf("attempt 2: expected exactly 200ms with no jitter, got %v", got)
+	}
+}
+
+func TestWithRetrySetsMaxAttemptsAndBackoff(t *testing.T) {
+	c, err := New("http://example.invalid", WithOIDCToken("token"), WithRetry(RetryConfig{
+		MaxAttempts: 4,
+		BaseDelay:   10 * time.Millisecond,
+		MaxDelay:    time.Second,
+		Jitter:      0.1,
+	}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if c.retrier.maxRetries != 3 {
+		t.Errorf("maxRetries = %d, want 3 (MaxAttempts 4 - 1)", c.retrier.maxRetries)
+	}
+	wait := c.retrier.backoff(0, 0)
+	if wait < 10*time.Millisecond || wait > 11*time.Millisecond {
+		t.Errorf("attempt 0 backoff = %v, want ~10-11ms", wait)
+	}
+}
+
+func TestWithRetryRetryablePredicateNarrowsClassification(t *testing.T) {
+	var attempts int
+	r := &Retrier{maxRetries: 5, retryWaitMin: time.Millisecond, retryWaitMax: time.Millisecond}
+
+	opts := &Options{}
+	WithRetry(RetryConfig{
+		Retryable: func(err error) bool { return IsServiceError(err) },
+	})(opts)
+	r.policy = opts.retryPolicy
+
+	err := r.Do(context.Background(), func() error {
+		attempts++
+		if attempts < 3 {
+			return &ServiceError{Code: "X", StatusCode: 503}
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetrierReturnsRetryAbortedErrorWrappingContextError(t *testing.T) {
+	r := &Retrier{maxRetries: 5, retryWaitMin: time.Hour, retryWaitMax: time.Hour}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var attempts int
+	err := r.Do(ctx, func() error {
+		attempts++
+		return &ServiceError{Code: "X", StatusCode: 500}
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected errors.Is(err, context.Canceled) via the RetryAbortedError Unwrap chain, got %v", err)
+	}
+	var abortedErr *RetryAbortedError
+	if !errors.As(err, &abortedErr) {
+		t.Fatalf("expected a *RetryAbortedError, got %v", err)
+	}
+	var serviceErr *ServiceError
+	if !errors.As(abortedErr.LastErr, &serviceErr) {
+		t.Errorf("expected RetryAbortedError.LastErr to be the last ServiceError, got %v", abortedErr.LastErr)
+	}
+	if attempts != 1 {
+		t.Errorf("expected exactly 1 attempt before the canceled context stops the retry loop, got %d", attempts)
+	}
+}
+
+func TestRetrierDeadlineExceededStopsRetryLoop(t *testing.T) {
+	r := &Retrier{maxRetries: 5, retryWaitMin: 50 * time.Millisecond, retryWaitMax: 50 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var attempts int
+	err := r.Do(ctx, func() error {
+		attempts++
+		return &ServiceError{Code: "X", StatusCode: 500}
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected errors.Is(err, context.DeadlineExceeded) via the RetryAbortedError Unwrap chain, got %v", err)
+	}
+	var abortedErr *RetryAbortedError
+	if !errors.As(err, &abortedErr) {
+		t.Fatalf("expected a *RetryAbortedError, got %v", err)
+	}
+	if attempts > 2 {
+		t.Errorf("expected the loop to stop shortly after the deadline, got %d attempts", attempts)
+	}
+}
+
+func TestRetrierContextCanceledDuringRateLimitWaitReturnsRetryAbortedError(t *testing.T) {
+	r := &Retrier{
+		maxRetries:       5,
+		retryWaitMin:     time.Hour,
+		retryWaitMax:     time.Hour,
+		retryOnRateLimit: true,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var attempts int
+	err := r.Do(ctx, func() error {
+		attempts++
+		return &RateLimitError{Message: "slow down", RetryAfter: time.Hour}
+	})
+
+	var abortedErr *RetryAbortedError
+	if !errors.As(err, &abortedErr) {
+		t.Fatalf("expected a *RetryAbortedError, got %v", err)
+	}
+	var rateLimitErr *RateLimitError
+	if !errors.As(abortedErr.LastErr, &rateLimitErr) {
+		t.Errorf("expected LastErr to be the RateLimitError, got %v", abortedErr.LastErr)
+	}
+	if abortedErr.Elapsed <= 0 {
+		t.Errorf("expected Elapsed to be positive, got %v", abortedErr.Elapsed)
+	}
+	if attempts != 1 {
+		t.Errorf("expected exactly 1 attempt before the deadline stops the loop during the rate-limit wait, got %d", attempts)
+	}
+}
@@ -2,6 +2,8 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -107,6 +109,30 @@ func TestSignWithRateLimitHeaders(t *testing.T) {
 	}
 }
 
+// TestSignServiceErrorCarriesRetryAfterHeader verifies that a 503 with a
+// Retry-After header populates ServiceError.RetryAfter, so the Retrier
+// can honor the server's requested delay instead of its own backoff.
+func TestSignServiceErrorCarriesRetryAfterHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"code": "INTERNAL_ERROR", "error": "overloaded"})
+	}))
+	defer server.Close()
+
+	c, _ := New(server.URL, WithMaxRetries(0))
+	_, err := c.Sign(context.Background(), "commit data", "")
+
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) {
+		t.Fatalf("expected a *ServiceError, got %v", err)
+	}
+	if serviceErr.RetryAfter != 5*time.Second {
+		t.Errorf("RetryAfter = %v, want 5s", serviceErr.RetryAfter)
+	}
+}
+
 // TestSignWithKeyID tests Sign() with specific keyID
 func TestSignWithKeyID(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
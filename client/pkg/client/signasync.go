@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// signCallOptions configures a single Sign call.
+type signCallOptions struct {
+	async          bool
+	idempotencyKey string
+}
+
+// SignOption configures a single Sign call, as opposed to Options which
+// configure the Client as a whole.
+type SignOption func(*signCallOptions)
+
+// Async, when true, makes Sign return as soon as the server has
+// accepted the signing request rather than waiting for it to complete.
+// The returned SignResult carries only JobID; pass it to WaitSignature
+// to retrieve the signature once the job finishes. Async bypasses
+// request signing and batch-transport coalescing, since both assume a
+// synchronous round trip.
+func Async(enable bool) SignOption {
+	return func(o *signCallOptions) {
+		o.async = enable
+	}
+}
+
+// signAsyncResponse is the JSON shape of a 202 response from POST
+// /sign?async=true.
+type signAsyncResponse struct {
+	JobID string `json:"jobId"`
+}
+
+// signAsync issues POST /sign?async=true, bypassing the generated
+// client since it has no async parameter, and returns a SignResult
+// carrying only the accepted job's ID.
+func (c *Client) signAsync(ctx context.Context, commitData, keyID string) (*SignResult, error) {
+	q := url.Values{"async": {"true"}}
+	if keyID != "" {
+		q.Set("keyId", keyID)
+	}
+
+	var jobID string
+	err := c.retrier.DoNonIdempotent(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/sign?"+q.Encode(), strings.NewReader(commitData))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "text/plain")
+		req.Header.Set("Authorization", c.authHeader())
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusAccepted {
+			return newUnexpectedStatusError(resp.StatusCode)
+		}
+
+		var body signAsyncResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return err
+		}
+		jobID = body.JobID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignResult{JobID: jobID}, nil
+}
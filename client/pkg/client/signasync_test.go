@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSignAsyncReturnsJobID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("async"); got != "true" {
+			t.Errorf("expected async=true query param, got %q", got)
+		}
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(w, `{"jobId":"job-async-1"}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithOIDCToken("token"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	result, err := c.Sign(context.Background(), "commit data", "", Async(true))
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if result.JobID != "job-async-1" {
+		t.Errorf("JobID = %q, want %q", result.JobID, "job-async-1")
+	}
+	if result.Signature != "" {
+		t.Errorf("expected empty Signature on an async accept, got %q", result.Signature)
+	}
+}
+
+func TestSignAsyncThenWaitSignature(t *testing.T) {
+	var polled bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sign", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(w, `{"jobId":"job-async-2"}`)
+	})
+	mux.HandleFunc("/sign/jobs/job-async-2", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !polled {
+			polled = true
+			fmt.Fprint(w, `{"status":"pending"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"done","signature":"-----BEGIN PGP SIGNATURE-----"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c, err := New(server.URL, WithOIDCToken("token"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	accepted, err := c.Sign(context.Background(), "commit data", "", Async(true))
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	result, err := c.WaitSignature(context.Background(), accepted.JobID, WithPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("WaitSignature: %v", err)
+	}
+	if result.Signature != "-----BEGIN PGP SIGNATURE-----" {
+		t.Errorf("unexpected signature: %q", result.Signature)
+	}
+}
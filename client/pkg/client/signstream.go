@@ -0,0 +1,205 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kjanat/gpg-signing-service/client/pkg/api"
+)
+
+// SignStream signs data read from r without buffering the whole payload
+// in memory: the request body streams with Transfer-Encoding: chunked
+// while a SHA-256 of the content is computed client-side as it passes
+// through. Pass an empty string for keyID to use the default key.
+func (c *Client) SignStream(ctx context.Context, r io.Reader, keyID string) (*SignResult, error) {
+	if r == nil {
+		return nil, &ValidationError{Code: "INVALID_REQUEST", Message: "reader cannot be nil"}
+	}
+
+	params := buildSignParams(keyID)
+	digest := sha256.New()
+
+	var resp *api.PostSignStreamResponse
+	var err error
+	if c.opts.requestBodyBuffering {
+		// The payload can be replayed from memory, so retry like any
+		// other operation, including on a mapped 5xx ServiceError: the
+		// oapi call only returns execErr for transport-level failures,
+		// so a status-coded error response has to be mapped and
+		// returned from inside the closure for the Retrier to see it.
+		buf, readErr := io.ReadAll(io.TeeReader(r, digest))
+		if readErr != nil {
+			return nil, readErr
+		}
+		err = c.retrier.Do(ctx, func() error {
+			var execErr error
+			resp, execErr = c.raw.PostSignStreamWithBodyWithResponse(ctx, params, "application/octet-stream", bytes.NewReader(buf))
+			if execErr != nil {
+				return execErr
+			}
+			return mapSignStreamResponseError(resp)
+		})
+	} else {
+		// r can only be consumed once: skip retry rather than risk
+		// sending a partially-read body on a second attempt.
+		resp, err = c.raw.PostSignStreamWithBodyWithResponse(ctx, params, "application/octet-stream", io.TeeReader(r, digest))
+		if err == nil {
+			err = mapSignStreamResponseError(resp)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SignResult{Signature: string(resp.Body)}
+	result.ContentSHA256 = hex.EncodeToString(digest.Sum(nil))
+	return result, nil
+}
+
+// mapSignStreamResponseError maps a non-200 PostSignStream response to
+// the same typed errors Sign returns (see mapSignResponseError), so
+// callers get the usual IsRateLimitError/IsServiceError detection and a
+// 5xx is retried by the Retrier exactly as it would be for Sign. It
+// returns nil for a 200 response.
+func mapSignStreamResponseError(resp *api.PostSignStreamResponse) error {
+	switch {
+	case resp.StatusCode() == 200:
+		return nil
+	case resp.JSON400 != nil:
+		return &ValidationError{
+			Code:    string(resp.JSON400.Code),
+			Message: resp.JSON400.Error,
+		}
+	case resp.JSON404 != nil:
+		return &ServiceError{
+			Code:       string(resp.JSON404.Code),
+			Message:    resp.JSON404.Error,
+			StatusCode: 404,
+		}
+	case resp.JSON429 != nil:
+		return &RateLimitError{
+			Message:    resp.JSON429.Error,
+			RetryAfter: time.Duration(resp.JSON429.RetryAfter) * time.Second,
+		}
+	case resp.JSON500 != nil:
+		return newServiceError(resp.HTTPResponse, string(resp.JSON500.Code), resp.JSON500.Error, 500)
+	case resp.JSON503 != nil:
+		return newServiceError(resp.HTTPResponse, string(resp.JSON503.Code), resp.JSON503.Error, 503)
+	default:
+		return newUnexpectedStatusError(resp.StatusCode())
+	}
+}
+
+// ChunkSource provides random-access reads over the payload being
+// uploaded by SignStreamResumable, so a failed chunk can be retried from
+// its own offset instead of restarting the whole upload.
+type ChunkSource interface {
+	io.ReaderAt
+	Size() int64
+}
+
+// defaultChunkSize is the amount of data uploaded per PATCH request by
+// SignStreamResumable.
+const defaultChunkSize = 8 * 1024 * 1024
+
+// SignStreamResumable signs a large payload (release tarballs, container
+// image manifests) using an upload-then-finalize pattern: a session is
+// opened, the payload is uploaded in Content-Range chunks that can be
+// retried independently from the last acknowledged offset, and a final
+// request closes the session and returns the signature.
+func (c *Client) SignStreamResumable(ctx context.Context, src ChunkSource, keyID string) (*SignResult, error) {
+	size := src.Size()
+	if size <= 0 {
+		return nil, &ValidationError{Code: "INVALID_REQUEST", Message: "src must be non-empty"}
+	}
+
+	sessionID, err := c.openUploadSession(ctx, keyID, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var offset int64
+	for offset < size {
+		end := offset + defaultChunkSize
+		if end > size {
+			end = size
+		}
+		chunk := make([]byte, end-offset)
+
+		err := c.retrier.Do(ctx, func() error {
+			if _, readErr := src.ReadAt(chunk, offset); readErr != nil && readErr != io.EOF {
+				return readErr
+			}
+			return c.uploadChunk(ctx, sessionID, offset, size, chunk)
+		})
+		if err != nil {
+			return nil, err
+		}
+		offset = end
+	}
+
+	return c.finalizeUpload(ctx, sessionID)
+}
+
+func (c *Client) openUploadSession(ctx context.Context, keyID string, size int64) (string, error) {
+	body := api.PostSignStreamSessionJSONRequestBody{
+		KeyId: keyIDPtrOrNil(keyID),
+		Size:  size,
+	}
+
+	var resp *api.PostSignStreamSessionResponse
+	err := c.retrier.Do(ctx, func() error {
+		var execErr error
+		resp, execErr = c.raw.PostSignStreamSessionWithResponse(ctx, body)
+		return execErr
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.JSON201 != nil {
+		return resp.JSON201.SessionId, nil
+	}
+	if resp.JSON400 != nil {
+		return "", &ValidationError{Code: string(resp.JSON400.Code), Message: resp.JSON400.Error}
+	}
+	return "", newUnexpectedStatusError(resp.StatusCode())
+}
+
+func (c *Client) uploadChunk(ctx context.Context, sessionID string, offset, total int64, chunk []byte) error {
+	params := &api.PatchSignStreamSessionIdParams{
+		ContentRange: fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(len(chunk))-1, total),
+	}
+
+	resp, err := c.raw.PatchSignStreamSessionIdWithBodyWithResponse(ctx, sessionID, params, "application/octet-stream", bytes.NewReader(chunk))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() != 204 {
+		return newUnexpectedStatusError(resp.StatusCode())
+	}
+	return nil
+}
+
+func (c *Client) finalizeUpload(ctx context.Context, sessionID string) (*SignResult, error) {
+	var resp *api.PutSignStreamSessionIdResponse
+	err := c.retrier.Do(ctx, func() error {
+		var execErr error
+		resp, execErr = c.raw.PutSignStreamSessionIdWithResponse(ctx, sessionID)
+		return execErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 != nil {
+		return &SignResult{Signature: resp.JSON200.Signature}, nil
+	}
+	if resp.JSON404 != nil {
+		return nil, &ServiceError{Code: string(resp.JSON404.Code), Message: resp.JSON404.Error, StatusCode: 404}
+	}
+	return nil, newUnexpectedStatusError(resp.StatusCode())
+}
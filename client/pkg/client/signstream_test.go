@@ -0,0 +1,189 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+)
+
+type bytesChunkSource []byte
+
+func (b bytesChunkSource) ReadAt(p []byte, off int64) (int, error) {
+	return copy(p, b[off:]), nil
+}
+
+func (b bytesChunkSource) Size() int64 {
+	return int64(len(b))
+}
+
+func TestSignStreamResumableRejectsEmptySource(t *testing.T) {
+	c := &Client{opts: defaultOptions()}
+	_, err := c.SignStreamResumable(nil, bytesChunkSource(nil), "")
+	if !IsValidationError(err) {
+		t.Errorf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestSignStreamRejectsNilReader(t *testing.T) {
+	c := &Client{opts: defaultOptions()}
+	_, err := c.SignStream(nil, nil, "")
+	if !IsValidationError(err) {
+		t.Errorf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestWithRequestBodyBufferingSetsOption(t *testing.T) {
+	opts := defaultOptions()
+	WithRequestBodyBuffering()(opts)
+	if !opts.requestBodyBuffering {
+		t.Error("expected requestBodyBuffering to be true")
+	}
+}
+
+// TestSignStreamMapsRateLimitError verifies that SignStream maps a 429
+// to *RateLimitError, with RetryAfter populated from the body, the same
+// way Sign does -- rather than collapsing it to the generic
+// newUnexpectedStatusError every other non-200 response used to get.
+func TestSignStreamMapsRateLimitError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": "RATE_LIMITED", "error": "slow down", "retryAfter": 3})
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	_, err = c.SignStream(context.Background(), bytes.NewReader([]byte("payload")), "")
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("expected a *RateLimitError, got %v", err)
+	}
+	if rateLimitErr.RetryAfter != 3*time.Second {
+		t.Errorf("RetryAfter = %v, want 3s", rateLimitErr.RetryAfter)
+	}
+	if !IsRateLimitError(err) {
+		t.Error("expected IsRateLimitError to report true")
+	}
+}
+
+// TestSignStreamRetriesOnBufferedServerError verifies that the buffered
+// (requestBodyBuffering) path retries a mapped 503 ServiceError, since
+// the oapi call reports it through the response rather than execErr and
+// the Retrier only sees it if SignStream returns it from inside the
+// retried closure.
+func TestSignStreamRetriesOnBufferedServerError(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(w).Encode(map[string]string{"code": "INTERNAL_ERROR", "error": "overloaded"})
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("signature"))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithRequestBodyBuffering())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	result, err := c.SignStream(context.Background(), bytes.NewReader([]byte("payload")), "")
+	if err != nil {
+		t.Fatalf("SignStream: %v", err)
+	}
+	if result.Signature != "signature" {
+		t.Errorf("Signature = %q, want %q", result.Signature, "signature")
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2 (one failure, one retry)", attempts)
+	}
+}
+
+// largeSignPayload is sized for the buffered-vs-streaming benchmarks
+// below. A 1 GiB payload makes BenchmarkSignBuffered painfully slow
+// under `go test -bench`, so
+// this stays at 256 MiB: still large enough that SignStream's flat
+// memory profile is visible against Sign's full-payload buffering.
+const largeSignPayloadSize = 256 * 1024 * 1024
+
+func benchmarkSignServer(b *testing.B) *httptest.Server {
+	b.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("signature"))
+	}))
+}
+
+// reportPeakHeap records HeapAlloc after forcing a GC, as a proxy for
+// peak RSS: Go's allocator doesn't expose true peak RSS, but HeapAlloc
+// after GC reflects the live working set the benchmarked path actually
+// held onto, which is what buffered-vs-streaming is meant to compare.
+func reportPeakHeap(b *testing.B) {
+	b.Helper()
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	b.Cleanup(func() {
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		if after.HeapAlloc > before.HeapAlloc {
+			b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc), "heap-bytes/op")
+		}
+	})
+}
+
+// BenchmarkSignBuffered signs a large payload through the default,
+// fully-buffered Sign path.
+func BenchmarkSignBuffered(b *testing.B) {
+	server := benchmarkSignServer(b)
+	defer server.Close()
+
+	c, err := New(server.URL)
+	if err != nil {
+		b.Fatalf("failed to create client: %v", err)
+	}
+	payload := string(make([]byte, largeSignPayloadSize))
+
+	reportPeakHeap(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Sign(context.Background(), payload, ""); err != nil {
+			b.Fatalf("sign: %v", err)
+		}
+	}
+}
+
+// BenchmarkSignStream signs the same size payload through SignStream,
+// which never holds the whole body in memory at once.
+func BenchmarkSignStream(b *testing.B) {
+	server := benchmarkSignServer(b)
+	defer server.Close()
+
+	c, err := New(server.URL)
+	if err != nil {
+		b.Fatalf("failed to create client: %v", err)
+	}
+	payload := make([]byte, largeSignPayloadSize)
+
+	reportPeakHeap(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.SignStream(context.Background(), bytes.NewReader(payload), ""); err != nil {
+			b.Fatalf("sign stream: %v", err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// unixSocketPlaceholderHost is the base URL handed to the generated API
+// client when talking over a Unix domain socket: the host/scheme are
+// never used to dial (DialContext always targets the socket path
+// instead), only to build well-formed request URLs and a Host header.
+const unixSocketPlaceholderHost = "http://unix-socket"
+
+// WithUnixSocket makes the client dial path, a Unix domain socket,
+// instead of resolving baseURL's host over TCP. This is the common
+// deployment shape for a locally-running signing daemon where TCP
+// shouldn't be exposed at all. baseURL may be given as-is (its host is
+// never dialed) or omitted in favor of a literal "unix://" baseURL, which
+// New recognizes and routes here automatically.
+func WithUnixSocket(path string) Option {
+	return func(o *Options) {
+		o.unixSocketPath = path
+	}
+}
+
+// WithTLSConfig sets a custom tls.Config for the client's transport, e.g.
+// to pin a certificate or customize verification. WithMTLS is a
+// convenience wrapper for the common client-certificate case; the two
+// can be combined, with WithMTLS's certificate and CA pool layered onto
+// the tls.Config supplied here.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *Options) {
+		o.tlsConfig = cfg
+	}
+}
+
+// WithMTLS configures the client to authenticate with a client
+// certificate (certFile, keyFile) and to trust server certificates
+// signed by caFile, for talking to a remote instance that requires
+// mutual TLS. Pass an empty caFile to use the system root pool. Files
+// are read and parsed when New is called, not when this option is
+// applied.
+func WithMTLS(certFile, keyFile, caFile string) Option {
+	return func(o *Options) {
+		o.tlsCertFile = certFile
+		o.tlsKeyFile = keyFile
+		o.tlsCAFile = caFile
+	}
+}
+
+// resolveUnixSocket determines the effective base URL and socket path to
+// use. An explicit WithUnixSocket path takes precedence; otherwise a
+// literal "unix://<path>" baseURL is rewritten to the placeholder host
+// so the generated API client can still build request URLs against it.
+func resolveUnixSocket(baseURL, optPath string) (effectiveBaseURL, socketPath string, err error) {
+	if optPath != "" {
+		return baseURL, optPath, nil
+	}
+	if !strings.HasPrefix(baseURL, "unix://") {
+		return baseURL, "", nil
+	}
+
+	path := strings.TrimPrefix(baseURL, "unix://")
+	if path == "" {
+		return "", "", fmt.Errorf("client: unix socket path cannot be empty")
+	}
+	return unixSocketPlaceholderHost, path, nil
+}
+
+// unixDialContext returns a DialContext that always dials socketPath
+// over a Unix domain socket, ignoring the network/addr the HTTP
+// transport would otherwise resolve from the request URL.
+func unixDialContext(socketPath string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", socketPath)
+	}
+}
+
+// buildMTLSConfig layers a client certificate and/or CA pool onto base
+// (which may be nil), returning a new tls.Config. Either certFile/keyFile
+// or caFile may be empty to skip that half of the configuration.
+func buildMTLSConfig(certFile, keyFile, caFile string, base *tls.Config) (*tls.Config, error) {
+	cfg := base.Clone()
+	if cfg == nil {
+		cfg = &tls.Config{}
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile != "" {
+		caCert, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("parse CA certificate %s: no valid PEM blocks found", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	return cfg, nil
+}
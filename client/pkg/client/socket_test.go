@@ -0,0 +1,186 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// generateTestCertPair returns a self-signed certificate and key (PEM
+// encoded) along with a CA PEM identical to the certificate, suitable
+// for exercising buildMTLSConfig without a real CA.
+func generateTestCertPair(t *testing.T) (certPEM, keyPEM, caPEM []byte) {
+	t.Helper()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "gpg-sign-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	keyDER, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return certPEM, keyPEM, certPEM
+}
+
+func TestResolveUnixSocketExplicitOption(t *testing.T) {
+	baseURL, path, err := resolveUnixSocket("http://localhost", "/var/run/gpg-sign.sock")
+	if err != nil {
+		t.Fatalf("resolveUnixSocket: %v", err)
+	}
+	if baseURL != "http://localhost" {
+		t.Errorf("expected baseURL unchanged, got %q", baseURL)
+	}
+	if path != "/var/run/gpg-sign.sock" {
+		t.Errorf("unexpected socket path %q", path)
+	}
+}
+
+func TestResolveUnixSocketFromSchemeURL(t *testing.T) {
+	baseURL, path, err := resolveUnixSocket("unix:///var/run/gpg-sign.sock", "")
+	if err != nil {
+		t.Fatalf("resolveUnixSocket: %v", err)
+	}
+	if baseURL != unixSocketPlaceholderHost {
+		t.Errorf("expected placeholder host, got %q", baseURL)
+	}
+	if path != "/var/run/gpg-sign.sock" {
+		t.Errorf("unexpected socket path %q", path)
+	}
+}
+
+func TestResolveUnixSocketRejectsEmptyPath(t *testing.T) {
+	if _, _, err := resolveUnixSocket("unix://", ""); err == nil {
+		t.Error("expected error for empty unix socket path")
+	}
+}
+
+func TestResolveUnixSocketPassesThroughNormalURL(t *testing.T) {
+	baseURL, path, err := resolveUnixSocket("http://localhost:8080", "")
+	if err != nil {
+		t.Fatalf("resolveUnixSocket: %v", err)
+	}
+	if baseURL != "http://localhost:8080" || path != "" {
+		t.Errorf("expected passthrough, got baseURL=%q path=%q", baseURL, path)
+	}
+}
+
+func TestNewRejectsRelativeUnixSocketPath(t *testing.T) {
+	_, err := New("http://localhost", WithUnixSocket("relative/path.sock"))
+	if err == nil {
+		t.Error("expected error for non-absolute unix socket path")
+	}
+}
+
+func TestNewWithUnixSocketDialsSocket(t *testing.T) {
+	dir := t.TempDir()
+	socketPath := filepath.Join(dir, "gpg-sign.sock")
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Listener.Close()
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	c, err := New("http://unix-socket", WithUnixSocket(socketPath))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	resp, err := c.httpClient.Get("http://unix-socket/anything")
+	if err != nil {
+		t.Fatalf("request over unix socket: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestBuildMTLSConfigLoadsCertAndCA(t *testing.T) {
+	certPEM, keyPEM, caPEM := generateTestCertPair(t)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	caFile := filepath.Join(dir, "ca.pem")
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	writeFile(t, caFile, caPEM)
+
+	cfg, err := buildMTLSConfig(certFile, keyFile, caFile, nil)
+	if err != nil {
+		t.Fatalf("buildMTLSConfig: %v", err)
+	}
+	if len(cfg.Certificates) != 1 {
+		t.Errorf("expected 1 client certificate, got %d", len(cfg.Certificates))
+	}
+	if cfg.RootCAs == nil {
+		t.Error("expected RootCAs to be set")
+	}
+}
+
+func TestBuildMTLSConfigRejectsInvalidCA(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	writeFile(t, caFile, []byte("not a certificate"))
+
+	if _, err := buildMTLSConfig("", "", caFile, nil); err == nil {
+		t.Error("expected error for invalid CA PEM")
+	}
+}
+
+func TestBuildMTLSConfigPreservesBaseConfig(t *testing.T) {
+	base := &tls.Config{ServerName: "gpg-sign.example.com"}
+	cfg, err := buildMTLSConfig("", "", "", base)
+	if err != nil {
+		t.Fatalf("buildMTLSConfig: %v", err)
+	}
+	if cfg.ServerName != "gpg-sign.example.com" {
+		t.Errorf("expected ServerName preserved, got %q", cfg.ServerName)
+	}
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
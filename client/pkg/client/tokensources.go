@@ -0,0 +1,182 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// WithTokenSource is the general entry point for wiring a pluggable
+// TokenSource into the client, superseding the frozen-string
+// WithOIDCToken/WithAdminToken for long-running processes: the token is
+// fetched (and cached until near its reported expiry) by the same
+// TokenRenewer machinery backing the platform-specific WithXxxOIDC
+// options.
+func WithTokenSource(source TokenSource, opts ...RenewerOption) Option {
+	return WithTokenRenewer(source, opts...)
+}
+
+// StaticToken returns a TokenSource that always yields token, with a
+// long TTL so the renewer effectively never refreshes it. It lets a
+// pre-existing static credential be wired through WithTokenSource like
+// any other source, e.g. in tests.
+func StaticToken(token string) TokenSource {
+	return staticTokenSource{token: token}
+}
+
+type staticTokenSource struct {
+	token string
+}
+
+func (s staticTokenSource) Token(context.Context) (string, time.Duration, error) {
+	return s.token, 24 * time.Hour, nil
+}
+
+// FileToken returns a TokenSource that reads its bearer token from path,
+// re-reading the file only when its mtime changes. This is the same
+// mechanism WithGenericOIDCFile uses for Kubernetes projected
+// service-account tokens, exposed directly for callers who want to
+// combine it with RenewerOption tuning via WithTokenSource.
+func FileToken(path string) TokenSource {
+	return &fileTokenSource{path: path}
+}
+
+// ExecToken returns a TokenSource that runs name with args and uses its
+// trimmed stdout as the bearer token, e.g. `gcloud auth print-identity-token`.
+// The output is assumed to be a JWT and refreshed ahead of its exp claim
+// like the built-in OIDC sources.
+func ExecToken(name string, args ...string) TokenSource {
+	return &execTokenSource{name: name, args: args}
+}
+
+type execTokenSource struct {
+	name string
+	args []string
+}
+
+func (e *execTokenSource) Token(ctx context.Context) (string, time.Duration, error) {
+	cmd := exec.CommandContext(ctx, e.name, e.args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", 0, fmt.Errorf("client: run token command %q: %w: %s", e.name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return tokenWithSkewTTL(strings.TrimSpace(stdout.String()))
+}
+
+// OIDCClientCredentialsConfig configures OIDCClientCredentials.
+type OIDCClientCredentialsConfig struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Audience     string
+	Scope        string
+	HTTPClient   *http.Client
+}
+
+// OIDCClientCredentials returns a TokenSource that performs an OAuth2
+// client_credentials grant against cfg.TokenURL, for machine-to-machine
+// integrations that aren't tied to a specific cloud workload identity
+// provider (those are covered by WithGitHubActionsOIDC /
+// WithGoogleCloudOIDC).
+func OIDCClientCredentials(cfg OIDCClientCredentialsConfig) TokenSource {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &clientCredentialsTokenSource{cfg: cfg, client: httpClient}
+}
+
+type clientCredentialsTokenSource struct {
+	cfg    OIDCClientCredentialsConfig
+	client *http.Client
+}
+
+func (c *clientCredentialsTokenSource) Token(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if c.cfg.Audience != "" {
+		form.Set("audience", c.cfg.Audience)
+	}
+	if c.cfg.Scope != "" {
+		form.Set("scope", c.cfg.Scope)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(c.cfg.ClientID, c.cfg.ClientSecret)
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := doJSON(c.client, req, &body); err != nil {
+		return "", 0, fmt.Errorf("client: client_credentials token request: %w", err)
+	}
+
+	ttl := time.Duration(body.ExpiresIn) * time.Second
+	if ttl > oidcRefreshSkew {
+		ttl -= oidcRefreshSkew
+	}
+	return body.AccessToken, ttl, nil
+}
+
+// VaultAppRole returns a TokenSource that logs in to a HashiCorp Vault
+// server's AppRole auth method (POST /v1/auth/approle/login) and uses the
+// resulting client token as the bearer token, refreshed ahead of the
+// lease's reported duration like the other built-in sources. It composes
+// with WithTokenSource the same way StaticToken or ExecToken do; there is
+// no separate Vault-specific client option.
+func VaultAppRole(addr, roleID, secretID string) TokenSource {
+	return &vaultAppRoleTokenSource{addr: addr, roleID: roleID, secretID: secretID, client: http.DefaultClient}
+}
+
+type vaultAppRoleTokenSource struct {
+	addr     string
+	roleID   string
+	secretID string
+	client   *http.Client
+}
+
+func (v *vaultAppRoleTokenSource) Token(ctx context.Context) (string, time.Duration, error) {
+	loginBody, err := json.Marshal(map[string]string{
+		"role_id":   v.roleID,
+		"secret_id": v.secretID,
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	loginURL := strings.TrimRight(v.addr, "/") + "/v1/auth/approle/login"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, loginURL, bytes.NewReader(loginBody))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int64  `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := doJSON(v.client, req, &resp); err != nil {
+		return "", 0, fmt.Errorf("client: vault approle login: %w", err)
+	}
+	if resp.Auth.ClientToken == "" {
+		return "", 0, fmt.Errorf("client: vault approle login: response had no client_token")
+	}
+
+	return resp.Auth.ClientToken, time.Duration(resp.Auth.LeaseDuration) * time.Second, nil
+}
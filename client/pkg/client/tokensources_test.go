@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestStaticTokenReturnsFixedValue(t *testing.T) {
+	source := StaticToken("fixed-token")
+
+	token, ttl, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if token != "fixed-token" {
+		t.Errorf("expected fixed-token, got %q", token)
+	}
+	if ttl <= 0 {
+		t.Errorf("expected positive ttl, got %v", ttl)
+	}
+}
+
+func TestFileTokenRereadsOnMtimeChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+
+	firstExp := time.Now().Add(time.Hour).Unix()
+	if err := os.WriteFile(path, []byte(makeTestJWT(t, firstExp)), 0o600); err != nil {
+		t.Fatalf("write token file: %v", err)
+	}
+
+	source := FileToken(path)
+	token, _, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if token != makeTestJWT(t, firstExp) {
+		t.Error("expected first read to return file contents")
+	}
+
+	// Force a distinct mtime so the re-read is observed even on
+	// filesystems with coarse mtime resolution.
+	later := time.Now().Add(time.Minute)
+	secondExp := time.Now().Add(2 * time.Hour).Unix()
+	if err := os.WriteFile(path, []byte(makeTestJWT(t, secondExp)), 0o600); err != nil {
+		t.Fatalf("rewrite token file: %v", err)
+	}
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	token, _, err = source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token (reread): %v", err)
+	}
+	if token != makeTestJWT(t, secondExp) {
+		t.Error("expected second read to pick up updated file contents")
+	}
+}
+
+func TestExecTokenRunsCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+	jwt := makeTestJWT(t, exp)
+
+	source := ExecToken("/bin/sh", "-c", "printf '%s' "+shellQuote(jwt))
+	token, _, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if token != jwt {
+		t.Errorf("expected %q, got %q", jwt, token)
+	}
+}
+
+func TestExecTokenReportsCommandFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	source := ExecToken("/bin/sh", "-c", "echo boom 1>&2; exit 1")
+	if _, _, err := source.Token(context.Background()); err == nil {
+		t.Error("expected error from failing command")
+	}
+}
+
+func shellQuote(s string) string {
+	return "'" + s + "'"
+}
+
+func TestOIDCClientCredentialsFetchesToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		if r.Form.Get("grant_type") != "client_credentials" {
+			t.Errorf("expected client_credentials grant, got %q", r.Form.Get("grant_type"))
+		}
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "client-id" || pass != "client-secret" {
+			t.Errorf("expected basic auth client-id/client-secret, got %q/%q", user, pass)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"abc123","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	source := OIDCClientCredentials(OIDCClientCredentialsConfig{
+		TokenURL:     server.URL,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+	})
+
+	token, ttl, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if token != "abc123" {
+		t.Errorf("expected abc123, got %q", token)
+	}
+	if ttl <= 0 || ttl > time.Hour {
+		t.Errorf("expected ttl under 1h with skew applied, got %v", ttl)
+	}
+}
+
+func TestVaultAppRoleLogsInAndReturnsClientToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/auth/approle/login" {
+			t.Errorf("expected /v1/auth/approle/login, got %s", r.URL.Path)
+		}
+
+		var body struct {
+			RoleID   string `json:"role_id"`
+			SecretID string `json:"secret_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request body: %v", err)
+		}
+		if body.RoleID != "role-1" || body.SecretID != "secret-1" {
+			t.Errorf("unexpected login payload: %+v", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"vault-token","lease_duration":3600}}`))
+	}))
+	defer server.Close()
+
+	source := VaultAppRole(server.URL, "role-1", "secret-1")
+
+	token, ttl, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if token != "vault-token" {
+		t.Errorf("expected vault-token, got %q", token)
+	}
+	if ttl != time.Hour {
+		t.Errorf("expected 1h lease duration, got %v", ttl)
+	}
+}
+
+func TestVaultAppRoleRejectsEmptyClientToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"","lease_duration":3600}}`))
+	}))
+	defer server.Close()
+
+	source := VaultAppRole(server.URL, "role-1", "secret-1")
+
+	if _, _, err := source.Token(context.Background()); err == nil {
+		t.Error("expected error for empty client_token")
+	}
+}
@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// defaultBatchSize and defaultBatchLinger bound how long the batch
+// transport waits to coalesce concurrent Sign calls before dispatching
+// whatever it has, or falling back to a single-item request.
+const (
+	defaultBatchSize   = 32
+	defaultBatchLinger = 10 * time.Millisecond
+)
+
+// signJob is one caller's pending Sign request, queued for coalescing by
+// the batch transport. result carries exactly one SignItemResult.
+type signJob struct {
+	ctx        context.Context
+	commitData string
+	keyID      string
+	result     chan SignItemResult
+}
+
+// batchTransport coalesces concurrent Sign calls into SignBatch requests
+// processed by a small worker pool, trading a few milliseconds of added
+// latency per call for far fewer HTTP round trips under load.
+type batchTransport struct {
+	client  *Client
+	queue   chan signJob
+	size    int
+	linger  time.Duration
+	workers int
+}
+
+// WithTransport enables the batching transport: concurrent Sign calls
+// are coalesced into POST /sign/batch requests handled by a worker pool
+// sized 2*GOMAXPROCS, instead of one HTTP request per call. Use
+// WithBatchSize and WithBatchLinger to tune coalescing.
+func WithTransport() Option {
+	return func(o *Options) {
+		o.useBatchTransport = true
+	}
+}
+
+// WithBatchSize caps how many pending Sign calls the batch transport
+// groups into a single POST /sign/batch request. The default is 32.
+func WithBatchSize(n int) Option {
+	return func(o *Options) {
+		o.batchSize = n
+	}
+}
+
+// WithBatchLinger sets how long the batch transport waits for more
+// pending Sign calls to arrive before dispatching whatever it has. A
+// single pending call past the linger deadline falls back to an
+// ordinary single-item Sign request. The default is 10ms.
+func WithBatchLinger(d time.Duration) Option {
+	return func(o *Options) {
+		o.batchLinger = d
+	}
+}
+
+func newBatchTransport(c *Client, size int, linger time.Duration) *batchTransport {
+	if size <= 0 {
+		size = defaultBatchSize
+	}
+	if linger <= 0 {
+		linger = defaultBatchLinger
+	}
+	workers := 2 * runtime.GOMAXPROCS(0)
+
+	t := &batchTransport{
+		client:  c,
+		queue:   make(chan signJob, size*workers),
+		size:    size,
+		linger:  linger,
+		workers: workers,
+	}
+	for i := 0; i < workers; i++ {
+		go t.runWorker()
+	}
+	return t
+}
+
+func (t *batchTransport) sign(ctx context.Context, commitData, keyID string) (*SignResult, error) {
+	job := signJob{ctx: ctx, commitData: commitData, keyID: keyID, result: make(chan SignItemResult, 1)}
+
+	select {
+	case t.queue <- job:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	select {
+	case res := <-job.result:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return &SignResult{Signature: res.Signature}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (t *batchTransport) runWorker() {
+	for {
+		jobs := t.collect()
+		if len(jobs) == 0 {
+			continue
+		}
+		t.dispatch(jobs)
+	}
+}
+
+// collect gathers up to t.size pending jobs, waiting at most t.linger
+// for more to arrive once the first job is received. dispatch groups
+// them by keyID.
+func (t *batchTransport) collect() []signJob {
+	first, ok := <-t.queue
+	if !ok {
+		return nil
+	}
+	jobs := []signJob{first}
+
+	deadline := time.NewTimer(t.linger)
+	defer deadline.Stop()
+
+	for len(jobs) < t.size {
+		select {
+		case job := <-t.queue:
+			jobs = append(jobs, job)
+		case <-deadline.C:
+			return jobs
+		}
+	}
+	return jobs
+}
+
+func (t *batchTransport) dispatch(jobs []signJob) {
+	byKey := make(map[string][]signJob)
+	for _, j := range jobs {
+		byKey[j.keyID] = append(byKey[j.keyID], j)
+	}
+
+	for keyID, group := range byKey {
+		if len(group) == 1 {
+			t.dispatchSingle(group[0])
+			continue
+		}
+		t.dispatchBatch(keyID, group)
+	}
+}
+
+func (t *batchTransport) dispatchSingle(job signJob) {
+	idemKey, err := newIdempotencyKey()
+	if err != nil {
+		job.result <- SignItemResult{Err: err}
+		return
+	}
+
+	result, err := t.client.signDirect(job.ctx, job.commitData, job.keyID, idemKey)
+	if err != nil {
+		job.result <- SignItemResult{Err: err}
+		return
+	}
+	job.result <- SignItemResult{Signature: result.Signature}
+}
+
+func (t *batchTransport) dispatchBatch(keyID string, group []signJob) {
+	items := make([]SignItem, len(group))
+	for i, j := range group {
+		items[i] = SignItem{CommitData: j.commitData, KeyID: j.keyID}
+	}
+
+	// Use a background context for the shared batch request: any single
+	// caller's context being canceled must not abort the others' work.
+	batchResult, err := t.client.SignBatch(context.Background(), items, keyID)
+	if err != nil {
+		for _, j := range group {
+			j.result <- SignItemResult{Err: err}
+		}
+		return
+	}
+
+	for i, j := range group {
+		if i < len(batchResult.Results) {
+			j.result <- batchResult.Results[i]
+		} else {
+			j.result <- SignItemResult{Err: ErrUnexpectedStatus}
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package client
+
+import "testing"
+
+func TestNewBatchTransportAppliesDefaults(t *testing.T) {
+	tr := newBatchTransport(&Client{}, 0, 0)
+	if tr.size != defaultBatchSize {
+		t.Errorf("expected default batch size %d, got %d", defaultBatchSize, tr.size)
+	}
+	if tr.linger != defaultBatchLinger {
+		t.Errorf("expected default linger %v, got %v", defaultBatchLinger, tr.linger)
+	}
+	if tr.workers <= 0 {
+		t.Errorf("expected positive worker count, got %d", tr.workers)
+	}
+}
@@ -0,0 +1,263 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// VerifyResult describes the outcome of verifying a detached signature.
+type VerifyResult struct {
+	Valid       bool
+	KeyID       string
+	Fingerprint string
+}
+
+// keyCache persists ASCII-armored public keys to disk under
+// dir/<fingerprint>.asc so Verify can check signatures without a network
+// round-trip, re-validating against the server at most once per ttl.
+type keyCache struct {
+	dir string
+	ttl time.Duration
+
+	mu sync.Mutex
+}
+
+func newKeyCache(dir string, ttl time.Duration) *keyCache {
+	return &keyCache{dir: dir, ttl: ttl}
+}
+
+// WithKeyCache enables an on-disk cache, rooted at dir, of public keys
+// fetched by Verify. A cached key is reused without a server round-trip
+// until ttl elapses, after which it is re-fetched and re-validated.
+// Passing 0 for ttl uses the default of 24 hours. Entries are also
+// invalidated early by a key_rotate event observed via
+// Client.WatchKeyRotations.
+func WithKeyCache(dir string, ttl time.Duration) Option {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	return func(o *Options) {
+		o.keyCache = newKeyCache(dir, ttl)
+	}
+}
+
+// Verify checks signature, a detached ASCII-armored signature produced by
+// Sign, against data using the service's public key for keyID (empty for
+// the default key). If the client was constructed with WithKeyCache, the
+// key is served from the on-disk cache when a fresh entry exists.
+func (c *Client) Verify(ctx context.Context, data []byte, signature string, keyID string) (*VerifyResult, error) {
+	armoredKey, err := c.resolvePublicKey(ctx, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve public key: %w", err)
+	}
+
+	keyRing, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+
+	signer, err := openpgp.CheckArmoredDetachedSignature(keyRing, bytes.NewReader(data), strings.NewReader(signature), nil)
+	if err != nil {
+		return &VerifyResult{Valid: false}, nil
+	}
+
+	result := &VerifyResult{Valid: true}
+	if signer != nil {
+		result.KeyID = signer.PrimaryKey.KeyIdString()
+		result.Fingerprint = fmt.Sprintf("%X", signer.PrimaryKey.Fingerprint)
+	}
+	return result, nil
+}
+
+// RefreshKeyCache discards the cached public key for keyID, if the client
+// was constructed with WithKeyCache, so the next Verify call re-fetches
+// and re-validates it against the server instead of serving a stale
+// cache entry. It is a no-op when no key cache is configured.
+func (c *Client) RefreshKeyCache(keyID string) {
+	if c.opts.keyCache == nil {
+		return
+	}
+	c.opts.keyCache.invalidate(keyID)
+}
+
+func (c *Client) resolvePublicKey(ctx context.Context, keyID string) (string, error) {
+	if c.opts.keyCache == nil {
+		return c.PublicKey(ctx, keyID)
+	}
+	return c.opts.keyCache.get(ctx, keyID, c.PublicKey)
+}
+
+type keyCacheMeta struct {
+	Fingerprint string    `json:"fingerprint"`
+	FetchedAt   time.Time `json:"fetchedAt"`
+}
+
+func (kc *keyCache) get(ctx context.Context, keyID string, fetch func(context.Context, string) (string, error)) (string, error) {
+	cacheKey := cacheKeyFor(keyID)
+
+	if armoredKey, err := kc.readFresh(cacheKey); err == nil {
+		return armoredKey, nil
+	}
+
+	armoredKey, err := fetch(ctx, keyID)
+	if err != nil {
+		return "", err
+	}
+
+	fingerprint, err := fingerprintOf(armoredKey)
+	if err != nil {
+		return "", err
+	}
+	if err := kc.store(cacheKey, fingerprint, armoredKey); err != nil {
+		return "", err
+	}
+	return armoredKey, nil
+}
+
+// readFresh returns the cached key for cacheKey if its metadata exists
+// and is within ttl, or an error otherwise (cache miss or expired).
+func (kc *keyCache) readFresh(cacheKey string) (string, error) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	metaBytes, err := os.ReadFile(kc.metaPath(cacheKey))
+	if err != nil {
+		return "", err
+	}
+	var meta keyCacheMeta
+	if err := json.Unmarshal(metaBytes, &meta); err != nil {
+		return "", err
+	}
+	if time.Since(meta.FetchedAt) >= kc.ttl {
+		return "", fmt.Errorf("key cache: entry for %q has expired", cacheKey)
+	}
+
+	armoredKey, err := os.ReadFile(kc.keyPath(meta.Fingerprint))
+	if err != nil {
+		return "", err
+	}
+	return string(armoredKey), nil
+}
+
+func (kc *keyCache) store(cacheKey, fingerprint, armoredKey string) error {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	if err := os.MkdirAll(kc.dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(kc.keyPath(fingerprint), []byte(armoredKey), 0o644); err != nil {
+		return err
+	}
+
+	metaBytes, err := json.Marshal(keyCacheMeta{Fingerprint: fingerprint, FetchedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(kc.metaPath(cacheKey), metaBytes, 0o644)
+}
+
+// invalidate discards cacheKey's cached metadata so the next Verify call
+// re-fetches and re-validates the key against the server. It is called
+// when WatchKeyRotations observes a key_rotate event for that key.
+func (kc *keyCache) invalidate(keyID string) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+	_ = os.Remove(kc.metaPath(cacheKeyFor(keyID)))
+}
+
+func (kc *keyCache) metaPath(cacheKey string) string {
+	return filepath.Join(kc.dir, cacheKey+".meta.json")
+}
+
+func (kc *keyCache) keyPath(fingerprint string) string {
+	return filepath.Join(kc.dir, fingerprint+".asc")
+}
+
+func cacheKeyFor(keyID string) string {
+	if keyID == "" {
+		return "default"
+	}
+	return keyID
+}
+
+func fingerprintOf(armoredKey string) (string, error) {
+	keyRing, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+	if err != nil {
+		return "", fmt.Errorf("parse public key: %w", err)
+	}
+	if len(keyRing) == 0 {
+		return "", fmt.Errorf("key ring is empty")
+	}
+	return fmt.Sprintf("%X", keyRing[0].PrimaryKey.Fingerprint), nil
+}
+
+// KeyRotationEvent reports that the service rotated the signing key for
+// KeyID, as observed through a key_rotate audit log entry.
+type KeyRotationEvent struct {
+	KeyID       string
+	Fingerprint string
+	Timestamp   time.Time
+}
+
+// WatchKeyRotations streams key_rotate audit events as they occur, so
+// long-running callers (e.g. a CI runner holding a cached public key) can
+// react without polling. If the client was constructed with
+// WithKeyCache, each observed rotation also invalidates that keyID's
+// cache entry, forcing the next Verify call to re-fetch the new key.
+func (c *Client) WatchKeyRotations(ctx context.Context) (<-chan KeyRotationEvent, error) {
+	events, errs := c.StreamAuditLogs(ctx, AuditFilter{Action: "key_rotate"})
+
+	out := make(chan KeyRotationEvent)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+				if c.opts.keyCache != nil {
+					c.opts.keyCache.invalidate(ev.KeyID)
+				}
+				select {
+				case out <- KeyRotationEvent{
+					KeyID:       ev.KeyID,
+					Fingerprint: fingerprintFromMetadata(ev.Metadata),
+					Timestamp:   ev.Timestamp,
+				}:
+				case <-ctx.Done():
+					return
+				}
+			case <-errs:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func fingerprintFromMetadata(metadata json.RawMessage) string {
+	if len(metadata) == 0 {
+		return ""
+	}
+	var m struct {
+		Fingerprint string `json:"fingerprint"`
+	}
+	if err := json.Unmarshal(metadata, &m); err != nil {
+		return ""
+	}
+	return m.Fingerprint
+}
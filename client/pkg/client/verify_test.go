@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCacheKeyForDefaultsEmptyKeyID(t *testing.T) {
+	if got := cacheKeyFor(""); got != "default" {
+		t.Errorf("cacheKeyFor(\"\") = %q, want \"default\"", got)
+	}
+	if got := cacheKeyFor("release-2026"); got != "release-2026" {
+		t.Errorf("cacheKeyFor(\"release-2026\") = %q, want \"release-2026\"", got)
+	}
+}
+
+func TestKeyCacheStoreAndReadFreshRoundTrip(t *testing.T) {
+	kc := newKeyCache(t.TempDir(), time.Hour)
+
+	const armoredKey = "-----BEGIN PGP PUBLIC KEY BLOCK-----\nstub\n-----END PGP PUBLIC KEY BLOCK-----\n"
+	if err := kc.store("default", "ABC123", armoredKey); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+
+	got, err := kc.readFresh("default")
+	if err != nil {
+		t.Fatalf("readFresh: %v", err)
+	}
+	if got != armoredKey {
+		t.Errorf("readFresh returned %q, want %q", got, armoredKey)
+	}
+}
+
+func TestKeyCacheReadFreshExpires(t *testing.T) {
+	kc := newKeyCache(t.TempDir(), -time.Second)
+
+	if err := kc.store("default", "ABC123", "stub-key"); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+
+	if _, err := kc.readFresh("default"); err == nil {
+		t.Error("expected readFresh to report an expired entry")
+	}
+}
+
+func TestKeyCacheInvalidateForcesRefetch(t *testing.T) {
+	kc := newKeyCache(t.TempDir(), time.Hour)
+
+	if err := kc.store("default", "ABC123", "stub-key"); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	kc.invalidate("")
+
+	if _, err := kc.readFresh("default"); err == nil {
+		t.Error("expected readFresh to miss after invalidate")
+	}
+}
+
+func TestKeyCacheGetFetchesOnMiss(t *testing.T) {
+	kc := newKeyCache(t.TempDir(), time.Hour)
+
+	calls := 0
+	fetch := func(context.Context, string) (string, error) {
+		calls++
+		return "-----BEGIN PGP PUBLIC KEY BLOCK-----\nstub\n-----END PGP PUBLIC KEY BLOCK-----\n", nil
+	}
+
+	if _, err := kc.get(context.Background(), "", fetch); err != nil {
+		// fingerprintOf will fail to parse the stub key; that's expected
+		// here since we only care that fetch was invoked on a cache miss.
+		if calls != 1 {
+			t.Errorf("expected fetch to be called once, got %d", calls)
+		}
+		return
+	}
+	t.Fatal("expected fingerprintOf to reject the stub key")
+}
+
+func TestKeyCacheGetReturnsFetchError(t *testing.T) {
+	kc := newKeyCache(t.TempDir(), time.Hour)
+	wantErr := errors.New("boom")
+
+	_, err := kc.get(context.Background(), "", func(context.Context, string) (string, error) {
+		return "", wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("expected fetch error to propagate, got %v", err)
+	}
+}
+
+func TestRefreshKeyCacheForcesRefetch(t *testing.T) {
+	c := &Client{opts: defaultOptions()}
+	c.opts.keyCache = newKeyCache(t.TempDir(), time.Hour)
+
+	if err := c.opts.keyCache.store("default", "ABC123", "stub-key"); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+
+	c.RefreshKeyCache("")
+
+	if _, err := c.opts.keyCache.readFresh("default"); err == nil {
+		t.Error("expected readFresh to miss after RefreshKeyCache")
+	}
+}
+
+func TestRefreshKeyCacheNoopWithoutCache(t *testing.T) {
+	c := &Client{opts: defaultOptions()}
+	c.RefreshKeyCache("anything") // must not panic
+}
+
+func TestFingerprintFromMetadata(t *testing.T) {
+	metadata := json.RawMessage(`{"fingerprint":"DEADBEEF"}`)
+	if got := fingerprintFromMetadata(metadata); got != "DEADBEEF" {
+		t.Errorf("fingerprintFromMetadata = %q, want DEADBEEF", got)
+	}
+	if got := fingerprintFromMetadata(nil); got != "" {
+		t.Errorf("fingerprintFromMetadata(nil) = %q, want empty", got)
+	}
+	if got := fingerprintFromMetadata(json.RawMessage(`not json`)); got != "" {
+		t.Errorf("fingerprintFromMetadata(invalid) = %q, want empty", got)
+	}
+}
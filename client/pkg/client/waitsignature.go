@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SignJobStatus is the lifecycle state of an asynchronous signing job, as
+// reported by GET /sign/jobs/{id}.
+type SignJobStatus string
+
+// Sign job statuses.
+const (
+	SignJobPending SignJobStatus = "pending"
+	SignJobDone    SignJobStatus = "done"
+	SignJobFailed  SignJobStatus = "failed"
+)
+
+// SignJobError indicates an asynchronous signing job referenced by JobID
+// reached status "failed".
+type SignJobError struct {
+	JobID   string
+	Code    string
+	Message string
+}
+
+func (e *SignJobError) Error() string {
+	return fmt.Sprintf("sign job %s failed: %s: %s", e.JobID, e.Code, e.Message)
+}
+
+// IsSignJobFailed returns true if the error indicates an asynchronous
+// signing job finished in a failed state.
+func IsSignJobFailed(err error) bool {
+	var je *SignJobError
+	return errors.As(err, &je)
+}
+
+type signJobResponse struct {
+	Status             SignJobStatus `json:"status"`
+	Signature          string        `json:"signature,omitempty"`
+	RateLimitRemaining *int          `json:"rateLimitRemaining,omitempty"`
+	RateLimitReset     *time.Time    `json:"rateLimitReset,omitempty"`
+	Code               string        `json:"code,omitempty"`
+	Error              string        `json:"error,omitempty"`
+}
+
+// waitOptions configures WaitSignature.
+type waitOptions struct {
+	pollInterval    time.Duration
+	maxPollInterval time.Duration
+}
+
+func defaultWaitOptions() *waitOptions {
+	return &waitOptions{
+		pollInterval:    1 * time.Second,
+		maxPollInterval: 30 * time.Second,
+	}
+}
+
+// WaitOption configures WaitSignature's polling behavior.
+type WaitOption func(*waitOptions)
+
+// WithPollInterval sets the initial interval between polls used when the
+// server doesn't send a Retry-After header. Defaults to 1s, doubling
+// after each poll up to WithMaxPollInterval.
+func WithPollInterval(d time.Duration) WaitOption {
+	return func(o *waitOptions) {
+		o.pollInterval = d
+	}
+}
+
+// WithMaxPollInterval caps the exponential backoff applied between polls
+// when the server doesn't send a Retry-After header. Defaults to 30s.
+func WithMaxPollInterval(d time.Duration) WaitOption {
+	return func(o *waitOptions) {
+		o.maxPollInterval = d
+	}
+}
+
+// WaitSignature polls GET /sign/jobs/{id} until the asynchronous signing
+// job id reaches a terminal state, hiding the polling loop behind a
+// single call in the spirit of the ACME protocol's WaitAuthorization. It
+// honors the server's Retry-After header between polls, falling back to
+// a caller-configurable interval that backs off exponentially up to
+// MaxPollInterval when the server sends none. Transient 5xx responses
+// are retried through the client's configured Retrier rather than
+// treated as terminal. The loop returns early if ctx is done.
+func (c *Client) WaitSignature(ctx context.Context, id string, opts ...WaitOption) (*SignResult, error) {
+	o := defaultWaitOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	wait := o.pollInterval
+	for {
+		job, retryAfter, err := c.pollSignJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch job.Status {
+		case SignJobDone:
+			return &SignResult{
+				Signature:          job.Signature,
+				RateLimitRemaining: job.RateLimitRemaining,
+				RateLimitReset:     job.RateLimitReset,
+			}, nil
+		case SignJobFailed:
+			return nil, &SignJobError{JobID: id, Code: job.Code, Message: job.Error}
+		}
+
+		// retryAfter is negative when the server sent no Retry-After
+		// header; zero is a valid "poll again immediately" value.
+		next := wait
+		if retryAfter >= 0 {
+			next = retryAfter
+		}
+
+		select {
+		case <-time.After(next):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if retryAfter < 0 {
+			wait *= 2
+			if wait > o.maxPollInterval {
+				wait = o.maxPollInterval
+			}
+		}
+	}
+}
+
+// pollSignJob issues a single GET /sign/jobs/{id}, retrying transient 5xx
+// failures via the client's Retrier.
+func (c *Client) pollSignJob(ctx context.Context, id string) (*signJobResponse, time.Duration, error) {
+	var job signJobResponse
+	retryAfter := time.Duration(-1) // sentinel: no Retry-After header seen
+
+	err := c.retrier.Do(ctx, func() error {
+		url := c.baseURL + "/sign/jobs/" + id
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", c.authHeader())
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			retryAfter = d
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			return &ServiceError{Code: ErrCodeJobNotFound, Message: "sign job not found", StatusCode: 404}
+		}
+		if resp.StatusCode >= 500 {
+			return &ServiceError{Code: "INTERNAL_ERROR", Message: "sign job status check failed", StatusCode: resp.StatusCode}
+		}
+		if resp.StatusCode != http.StatusOK {
+			return newUnexpectedStatusError(resp.StatusCode)
+		}
+
+		job = signJobResponse{}
+		return json.NewDecoder(resp.Body).Decode(&job)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return &job, retryAfter, nil
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitSignaturePollsUntilDone(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := polls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if n < 3 {
+			json.NewEncoder(w).Encode(signJobResponse{Status: SignJobPending})
+			return
+		}
+		json.NewEncoder(w).Encode(signJobResponse{Status: SignJobDone, Signature: "-----BEGIN PGP SIGNATURE-----"})
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithOIDCToken("token"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	result, err := c.WaitSignature(context.Background(), "job-1", WithPollInterval(time.Millisecond), WithMaxPollInterval(5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("WaitSignature: %v", err)
+	}
+	if result.Signature != "-----BEGIN PGP SIGNATURE-----" {
+		t.Errorf("unexpected signature: %q", result.Signature)
+	}
+	if polls.Load() != 3 {
+		t.Errorf("expected 3 polls, got %d", polls.Load())
+	}
+}
+
+func TestWaitSignatureReturnsJobError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(signJobResponse{Status: SignJobFailed, Code: "KEY_REVOKED", Error: "signing key was revoked"})
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithOIDCToken("token"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	_, err = c.WaitSignature(context.Background(), "job-2", WithPollInterval(time.Millisecond))
+	if !IsSignJobFailed(err) {
+		t.Fatalf("expected SignJobError, got %v", err)
+	}
+}
+
+func TestWaitSignatureHonorsRetryAfterHeader(t *testing.T) {
+	var polls atomic.Int32
+	var gotDelay time.Duration
+	var lastPoll time.Time
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := polls.Add(1)
+		if !lastPoll.IsZero() {
+			gotDelay = time.Since(lastPoll)
+		}
+		lastPoll = time.Now()
+
+		w.Header().Set("Content-Type", "application/json")
+		if n < 2 {
+			w.Header().Set("Retry-After", "0")
+			json.NewEncoder(w).Encode(signJobResponse{Status: SignJobPending})
+			return
+		}
+		json.NewEncoder(w).Encode(signJobResponse{Status: SignJobDone, Signature: "sig"})
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithOIDCToken("token"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	// A large default poll interval would make this test slow if
+	// Retry-After weren't honored (it instructs an immediate retry).
+	_, err = c.WaitSignature(context.Background(), "job-3", WithPollInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("WaitSignature: %v", err)
+	}
+	if gotDelay > time.Second {
+		t.Errorf("expected Retry-After: 0 to be honored instead of the 1h poll interval, waited %v", gotDelay)
+	}
+}
+
+func TestWaitSignatureRespectsContextCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(signJobResponse{Status: SignJobPending})
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithOIDCToken("token"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = c.WaitSignature(ctx, "job-4", WithPollInterval(5*time.Millisecond))
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestWaitSignatureNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithOIDCToken("token"), WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	_, err = c.WaitSignature(context.Background(), "missing-job", WithPollInterval(time.Millisecond))
+	se, ok := err.(*ServiceError)
+	if !ok || se.Code != ErrCodeJobNotFound {
+		t.Errorf("expected ServiceError{Code: JOB_NOT_FOUND}, got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// AppInfo sets a User-Agent identifying appName/appVersion (appName alone
+// if appVersion is empty, and the client library's own identity if
+// appName is empty) and an X-Client-Version header carrying
+// clientVersion, so the server can tell
+// which SDK build sent a request without parsing User-Agent.
+func AppInfo(appName, appVersion, clientVersion string) func(http.RoundTripper) http.RoundTripper {
+	userAgent := "gpg-signing-client"
+	if appName != "" {
+		userAgent = appName
+		if appVersion != "" {
+			userAgent = fmt.Sprintf("%s/%s", appName, appVersion)
+		}
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &appInfoTransport{next: next, userAgent: userAgent, clientVersion: clientVersion}
+	}
+}
+
+type appInfoTransport struct {
+	next          http.RoundTripper
+	userAgent     string
+	clientVersion string
+}
+
+func (t *appInfoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	if t.clientVersion != "" {
+		req.Header.Set("X-Client-Version", t.clientVersion)
+	}
+	return t.next.RoundTrip(req)
+}
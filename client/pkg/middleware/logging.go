@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Logging logs method, URL, status, and duration for every request at
+// structured-log level via logger. Pass nil to use slog.Default().
+func Logging(logger *slog.Logger) func(http.RoundTripper) http.RoundTripper {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &loggingTransport{next: next, logger: logger}
+	}
+}
+
+type loggingTransport struct {
+	next   http.RoundTripper
+	logger *slog.Logger
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		t.logger.Error("http request failed",
+			"method", req.Method,
+			"url", req.URL.String(),
+			"duration", duration,
+			"error", err,
+		)
+		return resp, err
+	}
+
+	t.logger.Info("http request",
+		"method", req.Method,
+		"url", req.URL.String(),
+		"status", resp.StatusCode,
+		"duration", duration,
+	)
+	return resp, nil
+}
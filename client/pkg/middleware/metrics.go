@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics records request latency and status-class counts with
+// Prometheus, registered against reg (pass nil for
+// prometheus.DefaultRegisterer). The histogram is labeled by method and
+// status class ("2xx", "4xx", "5xx", ...); it deliberately excludes the
+// full URL and status code to keep cardinality bounded.
+func Metrics(reg prometheus.Registerer) func(http.RoundTripper) http.RoundTripper {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "gpg_signing_client",
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of outgoing HTTP requests made by the client.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "status_class"})
+
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gpg_signing_client",
+		Name:      "http_requests_total",
+		Help:      "Total outgoing HTTP requests made by the client, by status class.",
+	}, []string{"method", "status_class"})
+
+	reg.MustRegister(duration, requests)
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &metricsTransport{next: next, duration: duration, requests: requests}
+	}
+}
+
+type metricsTransport struct {
+	next     http.RoundTripper
+	duration *prometheus.HistogramVec
+	requests *prometheus.CounterVec
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Seconds()
+
+	class := "error"
+	if resp != nil {
+		class = statusClass(resp.StatusCode)
+	}
+
+	t.duration.WithLabelValues(req.Method, class).Observe(elapsed)
+	t.requests.WithLabelValues(req.Method, class).Inc()
+
+	return resp, err
+}
+
+func statusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeRoundTripper struct {
+	fn func(*http.Request) (*http.Response, error)
+}
+
+func (f fakeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f.fn(req)
+}
+
+func TestRequestIDSetsHeaderWhenAbsent(t *testing.T) {
+	var seen string
+	next := fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		seen = req.Header.Get("X-Request-ID")
+		return httptest.NewRecorder().Result(), nil
+	}}
+
+	rt := RequestID(func() string { return "fixed-id" })(next)
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+
+	if seen != "fixed-id" {
+		t.Errorf("expected X-Request-ID=fixed-id, got %q", seen)
+	}
+}
+
+func TestRequestIDPreservesExistingHeader(t *testing.T) {
+	var seen string
+	next := fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		seen = req.Header.Get("X-Request-ID")
+		return httptest.NewRecorder().Result(), nil
+	}}
+
+	rt := RequestID(func() string { return "should-not-be-used" })(next)
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	req.Header.Set("X-Request-ID", "caller-supplied")
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+
+	if seen != "caller-supplied" {
+		t.Errorf("expected caller-supplied ID to be preserved, got %q", seen)
+	}
+}
+
+func TestAppInfoSetsHeaders(t *testing.T) {
+	var gotUA, gotVersion string
+	next := fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		gotUA = req.Header.Get("User-Agent")
+		gotVersion = req.Header.Get("X-Client-Version")
+		return httptest.NewRecorder().Result(), nil
+	}}
+
+	rt := AppInfo("myapp", "1.2.3", "0.9.0")(next)
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+
+	if gotUA != "myapp/1.2.3" {
+		t.Errorf("expected User-Agent myapp/1.2.3, got %q", gotUA)
+	}
+	if gotVersion != "0.9.0" {
+		t.Errorf("expected X-Client-Version 0.9.0, got %q", gotVersion)
+	}
+}
+
+func TestAppInfoFallsBackWhenAppNameEmpty(t *testing.T) {
+	var gotUA string
+	next := fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		gotUA = req.Header.Get("User-Agent")
+		return httptest.NewRecorder().Result(), nil
+	}}
+
+	rt := AppInfo("", "", "")(next)
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+
+	if gotUA != "gpg-signing-client" {
+		t.Errorf("expected default User-Agent, got %q", gotUA)
+	}
+}
+
+func TestLoggingPropagatesTransportError(t *testing.T) {
+	wantErr := http.ErrHandlerTimeout
+	next := fakeRoundTripper{fn: func(*http.Request) (*http.Response, error) {
+		return nil, wantErr
+	}}
+
+	rt := Logging(nil)(next)
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	if _, err := rt.RoundTrip(req); err != wantErr {
+		t.Errorf("expected underlying error to propagate, got %v", err)
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	cases := map[int]string{200: "2xx", 201: "2xx", 404: "4xx", 500: "5xx"}
+	for status, want := range cases {
+		if got := statusClass(status); got != want {
+			t.Errorf("statusClass(%d) = %q, want %q", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OpenTelemetryTracing starts a client span named "gpg-signing-client.<method>"
+// around each request using tracerName (pass "" to use the package's
+// default tracer name), and injects the current span context into the
+// outgoing request as a W3C traceparent header via otel.GetTextMapPropagator.
+func OpenTelemetryTracing(tracerName string) func(http.RoundTripper) http.RoundTripper {
+	if tracerName == "" {
+		tracerName = "github.com/kjanat/gpg-signing-service/client/pkg/middleware"
+	}
+	tracer := otel.Tracer(tracerName)
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &tracingTransport{next: next, tracer: tracer}
+	}
+}
+
+type tracingTransport struct {
+	next   http.RoundTripper
+	tracer trace.Tracer
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := t.tracer.Start(req.Context(), fmt.Sprintf("gpg-signing-client.%s", req.Method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", req.URL.String()),
+		),
+	)
+	defer span.End()
+
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, fmt.Sprintf("http %d", resp.StatusCode))
+	}
+	return resp, nil
+}